@@ -36,3 +36,46 @@ func (b *Box) Height() int {
 func (b *Box) ToString() string {
 	return fmt.Sprintf("[T: %d, L: %d, B: %d, R: %d]", b.top, b.left, b.bottom, b.right)
 }
+
+// Returns the area that is inside both this Box and the other Box.
+func (b *Box) intersect(other *Box) Box {
+	r := Box{
+		top:    max(b.top, other.top),
+		left:   max(b.left, other.left),
+		bottom: min(b.bottom, other.bottom),
+		right:  min(b.right, other.right),
+	}
+	r.bottom = max(r.top, r.bottom)
+	r.right = max(r.left, r.right)
+	return r
+}
+
+// Returns this Box shrunk by the given amount of space on each side.
+func (b *Box) inset(s *Spacing) Box {
+	r := Box{
+		top:    b.top + s.Top,
+		left:   b.left + s.Left,
+		bottom: b.bottom - s.Bottom,
+		right:  b.right - s.Right,
+	}
+	r.bottom = max(r.top, r.bottom)
+	r.right = max(r.left, r.right)
+	return r
+}
+
+// Returns the start and end of this Box along an axis.
+func (b *Box) span(isVertical bool) (int, int) {
+	if isVertical {
+		return b.top, b.bottom
+	}
+	return b.left, b.right
+}
+
+// Set the start and end of this Box along an axis.
+func (b *Box) setSpan(isVertical bool, start, end int) {
+	if isVertical {
+		b.top, b.bottom = start, end
+	} else {
+		b.left, b.right = start, end
+	}
+}
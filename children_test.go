@@ -0,0 +1,115 @@
+package flextui
+
+import "testing"
+
+// Reports an error if c doesn't have exactly the wanted children, or if the
+// parent, first/last child and sibling links don't match the order of the
+// children.
+func assertChildren(t *testing.T, c *Component, want ...*Component) {
+	t.Helper()
+	if len(c.children) != len(want) {
+		t.Fatalf("Got %d children, want %d", len(c.children), len(want))
+	}
+	for i, child := range c.children {
+		if child != want[i] {
+			t.Errorf("Child %d is not the wanted child", i)
+		}
+		if child.parent != c {
+			t.Errorf("Child %d has the wrong parent", i)
+		}
+		var prev, next *Component
+		if i > 0 {
+			prev = want[i-1]
+		}
+		if i < len(want)-1 {
+			next = want[i+1]
+		}
+		if child.prevNeighbor != prev || child.nextNeighbor != next {
+			t.Errorf("Child %d has the wrong sibling links", i)
+		}
+	}
+
+	var first, last *Component
+	if len(want) > 0 {
+		first, last = want[0], want[len(want)-1]
+	}
+	if c.firstChild != first || c.lastChild != last {
+		t.Error("First or last child is wrong")
+	}
+}
+
+// Returns a parent with n children.
+func newParent(n int) (*Component, []*Component) {
+	parent := NewComponent()
+	children := make([]*Component, n)
+	for i := range children {
+		children[i] = NewComponent()
+		parent.AddChild(children[i])
+	}
+	return parent, children
+}
+
+func TestInsertChild(t *testing.T) {
+	parent, c := newParent(2)
+	d := NewComponent()
+	parent.InsertChild(1, d)
+	assertChildren(t, parent, c[0], d, c[1])
+
+	// Out of range indices are clamped
+	e, f := NewComponent(), NewComponent()
+	parent.InsertChild(-1, e)
+	parent.InsertChild(10, f)
+	assertChildren(t, parent, e, c[0], d, c[1], f)
+
+	// Inserting a child of another parent moves it
+	other, g := newParent(2)
+	parent.InsertChild(0, g[1])
+	assertChildren(t, other, g[0])
+	assertChildren(t, parent, g[1], e, c[0], d, c[1], f)
+
+	// Inserting a child of the same parent moves it within the parent
+	parent.InsertChild(0, f)
+	assertChildren(t, parent, f, g[1], e, c[0], d, c[1])
+}
+
+func TestRemoveChild(t *testing.T) {
+	parent, c := newParent(3)
+	parent.RemoveChild(c[1])
+	assertChildren(t, parent, c[0], c[2])
+	if c[1].parent != nil || c[1].prevNeighbor != nil || c[1].nextNeighbor != nil {
+		t.Error("Removed child is still linked")
+	}
+
+	parent.RemoveChild(c[0])
+	assertChildren(t, parent, c[2])
+	parent.RemoveChild(c[2])
+	assertChildren(t, parent)
+
+	// Removing a Component that is not a child does nothing
+	parent.RemoveChild(c[1])
+	assertChildren(t, parent)
+}
+
+func TestReplaceChild(t *testing.T) {
+	parent, c := newParent(3)
+	d := NewComponent()
+	parent.ReplaceChild(c[1], d)
+	assertChildren(t, parent, c[0], d, c[2])
+	if c[1].parent != nil {
+		t.Error("Replaced child still has a parent")
+	}
+
+	// Replacing with a child of the same parent moves it
+	parent.ReplaceChild(c[0], c[2])
+	assertChildren(t, parent, c[2], d)
+
+	// Replacing with a child of another parent moves it
+	other, e := newParent(2)
+	parent.ReplaceChild(d, e[0])
+	assertChildren(t, other, e[1])
+	assertChildren(t, parent, c[2], e[0])
+
+	// Replacing a child with itself does nothing
+	parent.ReplaceChild(c[2], c[2])
+	assertChildren(t, parent, c[2], e[0])
+}
@@ -1,19 +1,65 @@
 package flextui
 
 import (
-	"context"
-	"fmt"
-	"os"
-	"strings"
 	"sync"
-
-	"golang.org/x/term"
+	"sync/atomic"
 )
 
 const BLANK_CHAR = " "
 
+// The character that replaces the part of a line that doesn't fit when
+// content is truncated. See [Component.SetWrapMode].
+const ELLIPSIS_CHAR = "…"
+
+const (
+	Event_LayoutUpdated = iota // Triggered when UpdateLayout() recomputes a Component's layout
+)
+
+// How child Components are positioned along the direction they are laid out
+// in when they don't fill the whole Component. See [Component.SetJustify].
+const (
+	Justify_Start        = iota // Pack children at the start
+	Justify_End                 // Pack children at the end
+	Justify_Center              // Pack children in the center
+	Justify_SpaceBetween        // Put equal space between children
+	Justify_SpaceAround         // Put equal space around each child
+	Justify_SpaceEvenly         // Put equal space between children and the edges
+)
+
+// How child Components are positioned across the direction they are laid out
+// in. See [Component.SetAlign].
+const (
+	Align_Stretch = iota // Fill the whole cross axis, unless a cross length is set
+	Align_Start
+	Align_Center
+	Align_End
+)
+
+// How lines of content that are wider than a Component are fit into it. See
+// [Component.SetWrapMode].
+const (
+	WrapMode_Char           = iota // Wrap onto the next row at the last character that fits
+	WrapMode_Word                  // Wrap onto the next row between words where possible
+	WrapMode_None                  // Don't wrap, and clip what doesn't fit
+	WrapMode_EllipsisEnd           // Don't wrap, and replace the end of the line with an ellipsis
+	WrapMode_EllipsisMiddle        // Don't wrap, and replace the middle of the line with an ellipsis
+	WrapMode_EllipsisStart         // Don't wrap, and replace the start of the line with an ellipsis
+)
+
+// How rows of content are positioned horizontally within a Component. See
+// [Component.SetTextAlign].
+const (
+	TextAlign_Left = iota
+	TextAlign_Center
+	TextAlign_Right
+)
+
+// How content is positioned vertically within a Component when it has fewer
+// rows than the Component. See [Component.SetVerticalAlign].
 const (
-	Event_LayoutUpdated = iota // Triggered at the very end of UpdateLayout()
+	VerticalAlign_Top = iota
+	VerticalAlign_Middle
+	VerticalAlign_Bottom
 )
 
 // A Component represents a rectangular area on the screen that can have a
@@ -28,6 +74,9 @@ const (
 // For pre-built Components that are more advanced, see the
 // [github.com/computerdane/flextui/components] library.
 type Component struct {
+	// Moves this Component's Box by the given number of cells on each side,
+	// e.g. to scroll through content that doesn't fit. Call UpdateLayout() on
+	// this Component after changing it.
 	Scroll Scroll
 
 	box        Box
@@ -41,21 +90,62 @@ type Component struct {
 	prevNeighbor *Component
 	nextNeighbor *Component
 
-	hasFlexChild bool
-
-	content   content
-	colorFunc func(a ...any) string
-
-	grow            float64
-	childrenGrowSum float64
-
-	length            int
-	childrenLengthSum int
-
-	firstBlankRow     int
-	firstBlankColumns []int
-
-	cancelRender context.CancelFunc
+	content       content
+	style         Style
+	parentStyle   Style // The style inherited from the parent in the last layout
+	textAlign     int
+	verticalAlign int
+
+	grow float64
+
+	length         int
+	autoLength     bool
+	relativeLength Dimension
+	isRelative     bool // Whether the length is relativeLength
+
+	hidden bool
+
+	gridColumns    []Track
+	gridRows       []Track
+	gridRow        int
+	gridColumn     int
+	gridRowSpan    int
+	gridColumnSpan int
+
+	padding Spacing
+	margin  Spacing
+	gap     int
+
+	justify     int
+	align       int
+	crossLength int
+	wrap        bool
+	crossGap    int
+
+	minLength      int
+	maxLength      int
+	minCrossLength int
+	maxCrossLength int
+
+	overlays      []*Component
+	isOverlay     bool
+	anchor        int
+	offsetTop     int
+	offsetLeft    int
+	overlayWidth  int
+	overlayHeight int
+	zIndex        int
+
+	// The Box and Scroll from the last layout, and whether the layout of this
+	// Component or its descendants is out of date. The flags are atomic because
+	// descendants set them without holding this Component's lock.
+	layoutBox       Box
+	layoutScroll    Scroll
+	dirty           atomic.Bool
+	dirtyDescendant atomic.Bool
+
+	// Set on root Components that are bound to a Terminal
+	display *display
 
 	eventListeners map[int][]*func(*Component)
 
@@ -65,7 +155,10 @@ type Component struct {
 func NewComponent() *Component {
 	c := &Component{
 		grow:           1,
-		firstBlankRow:  -1,
+		gridRow:        -1,
+		gridColumn:     -1,
+		gridRowSpan:    1,
+		gridColumnSpan: 1,
 		eventListeners: make(map[int][]*func(*Component)),
 	}
 	return c
@@ -79,6 +172,22 @@ func (c *Component) Content() *string {
 	return c.content.value
 }
 
+func (c *Component) WrapMode() int {
+	return c.content.wrapMode
+}
+
+func (c *Component) Style() Style {
+	return c.style
+}
+
+func (c *Component) TextAlign() int {
+	return c.textAlign
+}
+
+func (c *Component) VerticalAlign() int {
+	return c.verticalAlign
+}
+
 func (c *Component) Grow() float64 {
 	return c.grow
 }
@@ -91,16 +200,155 @@ func (c *Component) Children() []*Component {
 	return c.children
 }
 
+func (c *Component) Hidden() bool {
+	return c.hidden
+}
+
 func (c *Component) Length() int {
 	return c.length
 }
 
+// Returns this Component's length as a Dimension, which is relative to its
+// parent if it was set by SetLengthDimension().
+func (c *Component) LengthDimension() Dimension {
+	if c.isRelative {
+		return c.relativeLength
+	}
+	return Cells(c.length)
+}
+
+func (c *Component) AutoLength() bool {
+	return c.autoLength
+}
+
+func (c *Component) Padding() Spacing {
+	return c.padding
+}
+
+func (c *Component) Margin() Spacing {
+	return c.margin
+}
+
+func (c *Component) Gap() int {
+	return c.gap
+}
+
+func (c *Component) Wrap() bool {
+	return c.wrap
+}
+
+func (c *Component) CrossGap() int {
+	return c.crossGap
+}
+
+func (c *Component) Justify() int {
+	return c.justify
+}
+
+func (c *Component) Align() int {
+	return c.align
+}
+
+func (c *Component) CrossLength() int {
+	return c.crossLength
+}
+
+func (c *Component) GridColumns() []Track {
+	return c.gridColumns
+}
+
+func (c *Component) GridRows() []Track {
+	return c.gridRows
+}
+
+// Returns the row and column of the grid cell this Component is placed at, or
+// -1 if it is placed automatically.
+func (c *Component) GridCell() (int, int) {
+	return c.gridRow, c.gridColumn
+}
+
+func (c *Component) GridSpan() (int, int) {
+	return c.gridRowSpan, c.gridColumnSpan
+}
+
+func (c *Component) MinLength() int {
+	return c.minLength
+}
+
+func (c *Component) MaxLength() int {
+	return c.maxLength
+}
+
+func (c *Component) MinCrossLength() int {
+	return c.minCrossLength
+}
+
+func (c *Component) MaxCrossLength() int {
+	return c.maxCrossLength
+}
+
+// Returns the Terminal that this Component renders to. This is the Terminal
+// bound to its root Component, or the Screen's Terminal if the root is not
+// bound to one.
+func (c *Component) Terminal() Terminal {
+	return c.rootDisplay().terminal
+}
+
+// Hide the cursor of the Terminal that this Component renders to. The cursor
+// stays hidden after Render().
+func (c *Component) HideCursor() {
+	c.rootDisplay().hideCursor()
+}
+
+// Show the cursor of the Terminal that this Component renders to. Render()
+// hides the cursor while drawing, and then shows it again at the position set
+// with CursorTo().
+func (c *Component) ShowCursor() {
+	c.rootDisplay().showCursor()
+}
+
+// Move the cursor of the Terminal that this Component renders to to the given
+// 1-indexed row and column.
+func (c *Component) CursorTo(row, col int) {
+	c.rootDisplay().cursorTo(row, col)
+}
+
+// Returns the Component at the top of this Component's tree.
+func (c *Component) root() *Component {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// Returns the rendering state of the Terminal that this Component renders to.
+func (c *Component) rootDisplay() *display {
+	root := c.root()
+	if root.display != nil {
+		return root.display
+	}
+	return Screen.display
+}
+
+// Bind this Component to a Terminal. The Component will always fill the entire
+// Terminal, and it and all of its children will render to it. Only
+// Components without a parent should be bound to a Terminal.
+func (c *Component) SetTerminal(terminal Terminal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.display = newDisplay(terminal)
+	c.markDirty()
+}
+
 // Change whether child Components are laid out vertically or horizontally.
 func (c *Component) SetIsVertical(isVertical bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.isVertical = isVertical
+	c.markDirty()
 }
 
 // Set this Component's text content.
@@ -109,6 +357,28 @@ func (c *Component) SetContent(content string) {
 	defer c.mu.Unlock()
 
 	c.content.setValue(&content)
+	c.markDirty()
+}
+
+// Set this Component's content to the text of the spans, each drawn with its
+// own Style on top of the Component's style.
+func (c *Component) SetSpans(spans ...Span) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.content.setSpans(spans)
+	c.markDirty()
+}
+
+// Set this Component's content to text with inline styles. See
+// [ParseMarkup] for the format.
+func (c *Component) SetMarkup(markup string) error {
+	spans, err := ParseMarkup(markup)
+	if err != nil {
+		return err
+	}
+	c.SetSpans(spans...)
+	return nil
 }
 
 // Set the Component's content based on its Box's dimensions. Useful for
@@ -119,20 +389,59 @@ func (c *Component) SetContentFunc(updateFunc func(*Box) string) {
 	defer c.mu.Unlock()
 
 	c.content.updateFunc = updateFunc
+	c.markDirty()
+}
+
+// Set how lines of content that are wider than the Component are fit into
+// it. By default, content wraps at the last character that fits
+// (WrapMode_Char).
+func (c *Component) SetWrapMode(wrapMode int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.content.setWrapMode(wrapMode)
+	c.markDirty()
+}
+
+// Set how each row of content is positioned horizontally within the
+// Component. Defaults to TextAlign_Left.
+func (c *Component) SetTextAlign(textAlign int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.textAlign = textAlign
+}
+
+// Set how content is positioned vertically within the Component when it has
+// fewer rows than the Component. Defaults to VerticalAlign_Top.
+func (c *Component) SetVerticalAlign(verticalAlign int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.verticalAlign = verticalAlign
+}
+
+// Set the colors and attributes that the Component's content and blank space
+// are drawn with. Foreground and background colors that aren't set are
+// inherited from the parent Component, so the colors of a Component also apply
+// to its children once the layout is updated.
+func (c *Component) SetStyle(style Style) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.style = style
+	c.markDirty()
 }
 
 // Set the Component's style using a function that can be called to add
 // ANSI color codes before rendering the Component's content. Pairs
 // well with the library [github.com/fatih/color] using a color's
 // [github.com/fatih/color.Color.SprintFunc].
+//
+// The style is read from the escape sequences that the function adds, so
+// this is the same as calling SetStyle(StyleFromColorFunc(colorFunc)).
 func (c *Component) SetColorFunc(colorFunc func(a ...any) string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Ensure that we re-render blank content with the new color
-	c.firstBlankRow = -1
-	c.firstBlankColumns = nil
-	c.colorFunc = colorFunc
+	c.SetStyle(StyleFromColorFunc(colorFunc))
 }
 
 // Set the Component's grow property. All Components have a default grow of 1.
@@ -142,13 +451,8 @@ func (c *Component) SetGrow(grow float64) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.parent != nil {
-		c.parent.mu.Lock()
-		defer c.parent.mu.Unlock()
-
-		c.parent.childrenGrowSum += grow - c.grow
-	}
 	c.grow = grow
+	c.markDirty()
 }
 
 // Set a custom length for a Component. Overrides the grow property and
@@ -158,28 +462,239 @@ func (c *Component) SetLength(length int) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.parent != nil {
-		c.parent.mu.Lock()
-		defer c.parent.mu.Unlock()
-
-		c.parent.childrenLengthSum += length - c.length
-		if c.length == 0 && length > 0 {
-			c.parent.hasFlexChild = true
-			c.parent.childrenGrowSum -= c.grow
-		} else if c.length > 0 && length == 0 {
-			hasFlexChild := false
-			for _, child := range c.parent.children {
-				if child == c {
-					continue
-				}
-				if child.length == 0 {
-					hasFlexChild = true
-				}
-			}
-			c.parent.hasFlexChild = hasFlexChild
-		}
-	}
 	c.length = length
+	c.relativeLength = Dimension{}
+	c.isRelative = false
+	if length > 0 {
+		c.autoLength = false
+	}
+	c.markDirty()
+}
+
+// Set a length for a Component that is relative to the length of its parent's
+// content box, such as Percent(30), Fraction(1, 3) or Percent(50).Minus(2).
+// Relative lengths are resolved in UpdateLayout(), and do not depend on the
+// neighbor Components, so they keep their proportions exactly. Like
+// SetLength(), this overrides the grow property. See also ParseDimension().
+func (c *Component) SetLengthDimension(length Dimension) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.length = 0
+	if length.Ratio == 0 {
+		c.length = max(0, length.Cells)
+	}
+	c.relativeLength = length
+	c.isRelative = true
+	c.autoLength = false
+	c.markDirty()
+}
+
+// Set whether this Component's length is determined by its content, like a
+// fixed length that is always up to date. Along a horizontal parent, the
+// length is the width of the longest line of content, and along a vertical
+// parent, it is the number of rows the content needs when wrapped to the
+// Component's width. Components with children fit their children instead.
+// Overrides the grow property, and resets the length set by SetLength().
+func (c *Component) SetAutoLength(autoLength bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.autoLength = autoLength
+	if autoLength {
+		c.length = 0
+		c.relativeLength = Dimension{}
+		c.isRelative = false
+	}
+	c.markDirty()
+}
+
+// Set whether this Component is hidden. A hidden Component takes up no space
+// in its parent's layout, so its neighbors reflow to fill the space, and it and
+// its children are not rendered. Its other properties are kept, so it appears
+// as before when it is shown again.
+func (c *Component) SetHidden(hidden bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hidden = hidden
+	c.markDirty()
+}
+
+// Set the space between this Component's Box and its content and child
+// Components. The padding is filled with blank space.
+func (c *Component) SetPadding(padding Spacing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.padding = padding
+	c.markDirty()
+}
+
+// Set the space around this Component's Box that separates it from its parent
+// and neighbor Components. Margins are not drawn, so the parent Component
+// shows through them.
+func (c *Component) SetMargin(margin Spacing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.margin = margin
+	c.markDirty()
+}
+
+// Set the number of blank cells between consecutive child Components along
+// the direction they are laid out in.
+func (c *Component) SetGap(gap int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gap = gap
+	c.markDirty()
+}
+
+// Change whether child Components that don't fit in this Component wrap onto
+// a new line (or column, if the layout is vertical). Lines are broken based on
+// the children's fixed lengths or min lengths, and flex children grow to fill
+// their line. Each line is as large as its largest child's cross length, and
+// lines without cross lengths share the remaining space.
+func (c *Component) SetWrap(wrap bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.wrap = wrap
+	c.markDirty()
+}
+
+// Set the number of blank cells between the lines of wrapped child
+// Components. See SetWrap().
+func (c *Component) SetCrossGap(crossGap int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.crossGap = crossGap
+	c.markDirty()
+}
+
+// Set how child Components are positioned along the direction they are laid
+// out in, using one of the flextui.Justify_* constants. Only has an effect
+// when there is space left over, i.e. when no child Component grows to fill
+// it.
+func (c *Component) SetJustify(justify int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.justify = justify
+	c.markDirty()
+}
+
+// Set how child Components are positioned across the direction they are laid
+// out in, using one of the flextui.Align_* constants.
+func (c *Component) SetAlign(align int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.align = align
+	c.markDirty()
+}
+
+// Set the size of this Component across the direction that its parent lays
+// out its children in. A cross length of 0 fills the whole cross axis.
+func (c *Component) SetCrossLength(crossLength int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.crossLength = crossLength
+	c.markDirty()
+}
+
+// Set the column tracks of this Component's grid layout. When a Component has
+// column or row tracks, it lays out its children in a grid instead of a flex
+// layout, and each child fills the grid cells that it is placed in, inside its
+// margins. Children are placed in the first free cells in order, unless they
+// are placed explicitly with SetGridCell(). The gap separates columns and the
+// cross gap separates rows. Set no tracks to go back to a flex layout.
+func (c *Component) SetGridColumns(tracks ...Track) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gridColumns = tracks
+	c.markDirty()
+}
+
+// Set the row tracks of this Component's grid layout. Rows needed for
+// children beyond these tracks are auto tracks. See SetGridColumns().
+func (c *Component) SetGridRows(tracks ...Track) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gridRows = tracks
+	c.markDirty()
+}
+
+// Set the row and column of the grid cell this Component is placed at in its
+// parent's grid layout, counting from 0. A row or column of -1 places the
+// Component automatically along that axis.
+func (c *Component) SetGridCell(row, column int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gridRow = row
+	c.gridColumn = column
+	c.markDirty()
+}
+
+// Set the number of rows and columns this Component spans in its parent's
+// grid layout. The default is 1 row and 1 column.
+func (c *Component) SetGridSpan(rows, columns int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gridRowSpan = max(1, rows)
+	c.gridColumnSpan = max(1, columns)
+	c.markDirty()
+}
+
+// Set the smallest length this Component can have along the direction that
+// its parent lays out its children in. If the parent is too small, the
+// Component will overflow it.
+func (c *Component) SetMinLength(minLength int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minLength = minLength
+	c.markDirty()
+}
+
+// Set the largest length this Component can have along the direction that its
+// parent lays out its children in. A max length of 0 means there is no limit.
+// Space that a flex Component can't grow into is given to its neighbors.
+func (c *Component) SetMaxLength(maxLength int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxLength = maxLength
+	c.markDirty()
+}
+
+// Set the smallest size this Component can have across the direction that its
+// parent lays out its children in.
+func (c *Component) SetMinCrossLength(minCrossLength int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.minCrossLength = minCrossLength
+	c.markDirty()
+}
+
+// Set the largest size this Component can have across the direction that its
+// parent lays out its children in. A max cross length of 0 means there is no
+// limit.
+func (c *Component) SetMaxCrossLength(maxCrossLength int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxCrossLength = maxCrossLength
+	c.markDirty()
 }
 
 // Removes all child Components from this Component.
@@ -187,35 +702,151 @@ func (c *Component) RemoveAllChildren() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	for _, child := range c.children {
+		child.parent = nil
+		child.prevNeighbor = nil
+		child.nextNeighbor = nil
+	}
+	c.children = nil
 	c.firstChild = nil
 	c.lastChild = nil
-	c.hasFlexChild = false
-	c.childrenGrowSum = 0
-	c.childrenLengthSum = 0
-	c.children = nil
+	c.markDescendantDirty()
 }
 
 // Adds a child Component to this Component. The order in which AddChild() is
-// called will determine the order of the child Components' layout.
+// called will determine the order of the child Components' layout. If child
+// already has a parent, it is removed from it first.
 func (c *Component) AddChild(child *Component) {
+	child.detach()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.insertChild(len(c.children), child)
+}
+
+// Inserts a child Component at the given index of this Component's children,
+// moving the children at and after the index one place later. The index is
+// clamped to the range of valid indices. If child already has a parent, it is
+// removed from it first.
+func (c *Component) InsertChild(index int, child *Component) {
+	child.detach()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.firstChild == nil {
-		c.firstChild = child
+	c.insertChild(index, child)
+}
+
+// Removes a child Component from this Component. Does nothing if child is not
+// a child of this Component.
+func (c *Component) RemoveChild(child *Component) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i := c.childIndex(child); i >= 0 {
+		c.removeChild(i)
+	}
+}
+
+// Moves a child Component so that it is at the given index of this
+// Component's children. The index is clamped to the range of valid indices.
+// Does nothing if child is not a child of this Component.
+func (c *Component) MoveChild(child *Component, index int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i := c.childIndex(child); i >= 0 {
+		c.removeChild(i)
+		c.insertChild(index, child)
+	}
+}
+
+// Replaces a child Component with a new Component in the same position. If
+// newChild already has a parent, it is removed from it first. Does nothing if
+// oldChild is not a child of this Component.
+func (c *Component) ReplaceChild(oldChild, newChild *Component) {
+	if oldChild == newChild {
+		return
+	}
+	newChild.detach()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i := c.childIndex(oldChild); i >= 0 {
+		c.removeChild(i)
+		c.insertChild(i, newChild)
 	}
-	if c.lastChild != nil {
-		child.prevNeighbor = c.lastChild
-		c.lastChild.nextNeighbor = child
+}
+
+// Removes this Component from the children or overlays of its parent, if it
+// has one. Must be called without holding the lock of the new parent, since
+// the old parent may be the same Component.
+func (c *Component) detach() {
+	parent := c.parent
+	if parent == nil {
+		return
 	}
-	c.lastChild = child
+	if c.isOverlay {
+		parent.RemoveOverlay(c)
+	} else {
+		parent.RemoveChild(c)
+	}
+}
+
+// Returns the index of a child Component, or -1 if it is not a child of this
+// Component.
+func (c *Component) childIndex(child *Component) int {
+	for i, ch := range c.children {
+		if ch == child {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Component) insertChild(index int, child *Component) {
+	index = max(0, min(index, len(c.children)))
+	c.children = append(c.children, nil)
+	copy(c.children[index+1:], c.children[index:])
+	c.children[index] = child
 	child.parent = c
-	c.children = append(c.children, child)
-	if child.length == 0 {
-		c.hasFlexChild = true
-		c.childrenGrowSum += child.grow
+	c.relinkChildren(index)
+	child.markDirty()
+}
+
+func (c *Component) removeChild(index int) {
+	child := c.children[index]
+	c.children = append(c.children[:index], c.children[index+1:]...)
+	child.parent = nil
+	child.prevNeighbor = nil
+	child.nextNeighbor = nil
+	c.relinkChildren(max(0, index-1))
+	c.markDescendantDirty()
+}
+
+// Update firstChild, lastChild and the neighbors of the child at index i after
+// c.children has changed around it.
+func (c *Component) relinkChildren(i int) {
+	c.firstChild = nil
+	c.lastChild = nil
+	if len(c.children) > 0 {
+		c.firstChild = c.children[0]
+		c.lastChild = c.children[len(c.children)-1]
+	}
+	if i < 0 || i >= len(c.children) {
+		return
+	}
+
+	child := c.children[i]
+	child.prevNeighbor = nil
+	child.nextNeighbor = nil
+	if i > 0 {
+		child.prevNeighbor = c.children[i-1]
+		c.children[i-1].nextNeighbor = child
+	}
+	if i < len(c.children)-1 {
+		child.nextNeighbor = c.children[i+1]
+		c.children[i+1].prevNeighbor = child
 	}
-	c.childrenLengthSum += child.length
 }
 
 // Attach an event listener to this component. Use the flextui.Event_*
@@ -241,140 +872,139 @@ func (c *Component) RemoveEventListener(event int, listener *func(*Component)) {
 	}
 }
 
+// Mark this Component's layout as out of date, so that the next UpdateLayout()
+// recomputes it and reruns its content function. Setters call this
+// automatically, so it is only needed when a content function depends on state
+// outside of the Component.
+func (c *Component) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.markDirty()
+}
+
+// Mark this Component's layout as out of date.
+func (c *Component) markDirty() {
+	c.dirty.Store(true)
+	if c.parent != nil {
+		c.parent.markDescendantDirty()
+	}
+}
+
+// Mark this Component and its ancestors as having a descendant whose layout is
+// out of date, so that UpdateLayout() visits them.
+func (c *Component) markDescendantDirty() {
+	for ancestor := c; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor.dirtyDescendant.Swap(true) {
+			return
+		}
+	}
+}
+
 // Updates the Box positions of this Component and all child Components.
 //
+// Only the parts of the layout that changed since the last UpdateLayout() are
+// recomputed: Components whose properties or Scroll changed, Components whose
+// Box changed as a result, and their children. The result is the same as
+// recomputing the whole layout.
+//
 // Useful for responding to layout changes triggered by screen resizing or user
 // actions.
 func (c *Component) UpdateLayout() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c == Screen {
-		// The Screen Component should always fit the terminal size
-		width, height, err := term.GetSize(int(os.Stdout.Fd()))
-		if err != nil {
-			fmt.Println("Error getting terminal size: ", err)
-			return
+	box := c.layoutBox
+	if c.display != nil {
+		// Components bound to a Terminal should always fit the terminal size.
+		// If the size can't be read, the size from the last layout is kept.
+		if width, height, err := c.display.terminal.Size(); err == nil {
+			box = Box{top: 0, left: 0, bottom: height, right: width}
 		}
-		Screen.box.top = 0
-		Screen.box.left = 0
-		Screen.box.bottom = height
-		Screen.box.right = width
+	} else if c.isOverlay {
+		// Overlays are positioned by their anchor instead of the flex layout
+		box = c.parent.overlayBox(c)
 	} else if c.parent != nil {
 		// All other Components use a flex layout based on the parent's box
-		nChildren := len(c.parent.children)
-		width := c.parent.box.Width()
-		height := c.parent.box.Height()
-		if c.parent.isVertical {
-			height = max(0, int(float64(height-c.parent.childrenLengthSum)/(c.parent.childrenGrowSum/c.grow)))
-		} else {
-			width = max(0, int(float64(width-c.parent.childrenLengthSum)/(c.parent.childrenGrowSum/c.grow)))
-		}
-
-		if c.parent.firstChild == c {
-			// The first child should have the same top/left as the parent
-			c.box.top = c.parent.box.top
-			c.box.left = c.parent.box.left
-		} else {
-			// The rest of the children should align the top/left of their box with the previous child's box
-			if c.parent.isVertical {
-				c.box.top = c.prevNeighbor.box.bottom
-				c.box.left = c.parent.box.left
-			} else {
-				c.box.top = c.parent.box.top
-				c.box.left = c.prevNeighbor.box.right
+		for i, childBox := range c.parent.childBoxes() {
+			if c.parent.children[i] == c {
+				box = childBox
 			}
 		}
-		if c.parent.lastChild == c {
-			// The last child will, by default, align its bottom/right with the parent
-			c.box.bottom = c.parent.box.bottom
-			c.box.right = c.parent.box.right
-
-			// If the last child doesn't have a neighbor with a
-			// flex layout, and if it also has a fixed length,
-			// don't align it with the parent
-			if !c.parent.hasFlexChild && c.length != 0 {
-				if c.parent.isVertical {
-					c.box.bottom = c.box.top + c.length
-				} else {
-					c.box.right = c.box.left + c.length
-				}
-			}
+	}
 
-			// If the last child 1) has a neighbor with a flex
-			// layout, 2) has a fixed length, and 3) is not the
-			// only child, snap it to the end of the parent, and
-			// update its neighbors to align with itself.
-			if c.parent.hasFlexChild && c.length != 0 && nChildren != 1 {
-				if c.parent.isVertical {
-					c.box.top = c.box.bottom - c.length
-				} else {
-					c.box.left = c.box.right - c.length
-				}
-				// Align previous children with this Component's new position
-				first := c.prevNeighbor
-				second := c
-				for first != nil && second != nil {
-					if c.parent.isVertical {
-						if first.box.bottom == second.box.top {
-							break
-						}
-						first.box.bottom = second.box.top
-						if first.length != 0 {
-							first.box.top = first.box.bottom - first.length
-						}
-					} else {
-						if first.box.right == second.box.left {
-							break
-						}
-						first.box.right = second.box.left
-						if first.length != 0 {
-							first.box.left = first.box.right - first.length
-						}
-					}
-					first = first.prevNeighbor
-					second = second.prevNeighbor
-				}
-			}
-		} else {
-			// Ensure we use the parent box's position for the cross-axis, and use the computed length if c.length is not provided
-			if c.parent.isVertical {
-				if c.length > 0 {
-					c.box.bottom = c.box.top + c.length
-				} else {
-					c.box.bottom = c.box.top + height
-				}
-				c.box.right = c.parent.box.right
-			} else {
-				c.box.bottom = c.parent.box.bottom
-				if c.length > 0 {
-					c.box.right = c.box.left + c.length
-				} else {
-					c.box.right = c.box.left + width
-				}
-			}
-		}
+	var parentStyle Style
+	if c.parent != nil {
+		parentStyle = c.parent.resolvedStyle()
+	}
+
+	c.updateLayout(box, parentStyle)
+}
+
+// Updates the layout of this Component and its children, given the Box that
+// its parent positioned it in, not including its scrolling, and the style that
+// it inherits from its parent. If neither the Box, the Scroll, the inherited
+// style, nor the properties of this Component or its descendants changed since
+// the last layout, only the Scroll of its descendants is checked.
+func (c *Component) updateLayout(box Box, parentStyle Style) {
+	// Hidden Components and their children take up no space
+	if c.hidden {
+		c.box = Box{}
+		return
+	}
 
-		// Apply scrolling
-		c.box.top -= c.Scroll.Top
-		c.box.left -= c.Scroll.Left
-		c.box.right -= c.Scroll.Right
-		c.box.bottom -= c.Scroll.Bottom
+	// Clear the flags before visiting the children, so that changes made
+	// while the layout is being updated are picked up by the next update
+	dirty := c.dirty.Swap(false)
+	dirtyDescendant := c.dirtyDescendant.Swap(false)
+
+	changed := dirty || box != c.layoutBox || c.Scroll != c.layoutScroll
+	if !changed && !dirtyDescendant && parentStyle == c.parentStyle {
+		// Scroll is a field that can change without marking the layout as
+		// out of date, so the children are visited with their last Boxes
+		style := c.resolvedStyle()
+		for _, child := range c.children {
+			child.mu.Lock()
+			child.updateLayout(child.layoutBox, style)
+			child.mu.Unlock()
+		}
+		for _, overlay := range c.overlays {
+			overlay.mu.Lock()
+			overlay.updateLayout(overlay.layoutBox, style)
+			overlay.mu.Unlock()
+		}
+		return
 	}
+	c.layoutBox = box
+	c.layoutScroll = c.Scroll
+	c.parentStyle = parentStyle
+
+	// Apply scrolling
+	c.box = box
+	c.box.top -= c.Scroll.Top
+	c.box.left -= c.Scroll.Left
+	c.box.right -= c.Scroll.Right
+	c.box.bottom -= c.Scroll.Bottom
 
 	// Update content according to contentFunc
-	if c.content.updateFunc != nil {
-		value := c.content.updateFunc(&c.box)
+	if changed && c.content.updateFunc != nil {
+		contentBox := c.contentBox()
+		value := c.content.updateFunc(&contentBox)
 		c.content.setValue(&value)
 	}
 
-	// Reset the blank areas since the layout may have changed
-	c.firstBlankRow = -1
-	c.firstBlankColumns = nil
-
 	// Recursively update all children
-	for _, child := range c.children {
-		child.UpdateLayout()
+	style := c.resolvedStyle()
+	for i, box := range c.childBoxes() {
+		child := c.children[i]
+		child.mu.Lock()
+		child.updateLayout(box, style)
+		child.mu.Unlock()
+	}
+	for _, overlay := range c.overlays {
+		overlay.mu.Lock()
+		overlay.updateLayout(c.overlayBox(overlay), style)
+		overlay.mu.Unlock()
 	}
 
 	for _, handler := range c.eventListeners[Event_LayoutUpdated] {
@@ -382,196 +1012,164 @@ func (c *Component) UpdateLayout() {
 	}
 }
 
-func (c *Component) blankLine(width int) string {
-	blankLine := strings.Repeat(BLANK_CHAR, max(0, width))
-	return blankLine
-}
-
-func (c *Component) clipWithBounds(result *string, bounds *Box) {
-	clipA := max(0, min(len(*result)-1, bounds.left-c.box.left))
-	clipB := max(clipA, len(*result)-max(0, c.box.right-bounds.right))
-	*result = (*result)[clipA:clipB]
-}
-
 // Render this Component's content to the screen, and render all child
-// Components as well.
+// Components as well. Overlays that cover this Component are rendered again so
+// that they stay on top.
+//
+// Components are drawn into a frame of cells shared by all Components that
+// render to the same Terminal, and only the cells that changed since the last
+// Render() are written to the Terminal. It is safe to call Render() on
+// multiple Components concurrently.
 func (c *Component) Render() {
-	if c.cancelRender != nil {
-		c.cancelRender()
+	d := c.rootDisplay()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.resize()
+
+	// Restore the areas that removed overlays covered
+	root := c.root()
+	for _, box := range d.exposed {
+		root.draw(&d.back, box)
 	}
+	d.exposed = nil
+
+	c.draw(&d.back, c.bounds(&d.back))
+	c.drawCoveringOverlays(&d.back)
+	d.flush()
+}
+
+// Draw this Component and all child Components into the frame, without drawing
+// outside of the bounds, and then draw their overlays on top.
+func (c *Component) draw(f *frame, bounds Box) {
+	c.drawTree(f, bounds)
+	c.drawOverlays(f)
+}
 
+// Draw this Component and all child Components into the frame, without drawing
+// outside of the bounds or drawing any overlays.
+func (c *Component) drawTree(f *frame, bounds Box) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	c.cancelRender = cancel
-
-	// Recursively render all children
-	startRow := 0
-	for i, child := range c.children {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-		child.Render()
-		if i == len(c.children)-1 {
-			startRow = child.box.bottom - c.children[0].box.top
-		}
+	if c.hidden {
+		return
 	}
 
-	var builder strings.Builder
+	bounds = bounds.intersect(&c.box)
+	if bounds.Width() == 0 || bounds.Height() == 0 {
+		return
+	}
 
-	// Always hide the cursor when rendering
-	builder.WriteString("\033[?25l")
+	c.drawContent(f, &bounds)
 
-	// Show the cursor again if it was previously already showing
-	if !cursorHidden {
-		defer CursorTo(cursorRow, cursorCol)
-		defer ShowCursor()
+	// Recursively draw all children on top of this Component
+	for _, child := range c.children {
+		child.drawTree(f, bounds)
 	}
+}
 
-	width := c.box.Width()
-	height := c.box.Height()
-	firstBlankRow := -1                      // Will become the new value of c.firstBlankRow
-	firstBlankColumns := make([]int, height) // Will become the new value of c.firstBlankColumns
-	var a, b int                             // The start and end index of the content substring we are rendering
-	var contentLen int
-	var blankLine string
-
-	// We want to handle things more efficiently if the content is blank
-	isBlank := c.content.value == nil || *c.content.value == ""
+// Draw the overlays of this Component and all child Components into the
+// frame, in order of z-index. The overlays of children are drawn first, so
+// that the overlays of their ancestors stay on top. Overlays are only clipped
+// to the frame.
+func (c *Component) drawOverlays(f *frame) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	// If the screen is already clear, skip rendering
-	if isBlank && c.firstBlankRow == 0 {
+	if c.hidden {
 		return
 	}
-
-	if !isBlank {
-		contentLen = c.content.displayLen()
+	for _, child := range c.children {
+		child.drawOverlays(f)
 	}
-
-	// Loop through all parents to find bounding box so we can detect overflow
-	bounds := c.box
-	parent := c.parent
-	for parent != nil {
-		bounds.top = min(parent.box.bottom, max(parent.box.top, bounds.top))
-		bounds.left = min(parent.box.right, max(parent.box.left, bounds.left))
-		bounds.bottom = min(parent.box.bottom, max(parent.box.top, bounds.bottom))
-		bounds.right = min(parent.box.right, max(parent.box.left, bounds.right))
-		parent = parent.parent
+	for _, overlay := range c.sortedOverlays() {
+		overlay.draw(f, f.box())
 	}
+}
 
-	// Check if we are out of bounds
-	if c.box.top+startRow+height <= bounds.top || c.box.top+startRow >= bounds.bottom || c.box.left >= bounds.right {
-		return
-	}
+// Returns the style that this Component is drawn with: its own style, with the
+// colors that it doesn't set inherited from its parent.
+func (c *Component) resolvedStyle() Style {
+	return c.style.inherit(c.parentStyle)
+}
 
-	// Construct our output line-by-line
-	for row := startRow; row < height; row++ {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			// Uncomment to view rendering order and test race conditions
-			// time.Sleep(1 * time.Millisecond)
+// Draw this Component's content, and fill the rest of its box with blank
+// cells.
+func (c *Component) drawContent(f *frame, bounds *Box) {
+	style := c.resolvedStyle()
+	blank := Cell{Content: BLANK_CHAR, Width: 1, Style: style}
+	for top := bounds.top; top < bounds.bottom; top++ {
+		for left := bounds.left; left < bounds.right; left++ {
+			f.set(top, left, blank)
 		}
+	}
 
-		top := c.box.top + row
+	if c.content.value == nil {
+		return
+	}
+	value := *c.content.value
+	gs := c.content.graphemes
+
+	contentBox := c.contentBox()
+	rows := c.content.wrap(contentBox.Width())
+	rows = rows[:max(0, min(len(rows), contentBox.Height()))]
+
+	// Align the rows vertically
+	rowsTop := contentBox.top
+	switch c.verticalAlign {
+	case VerticalAlign_Middle:
+		rowsTop += (contentBox.Height() - len(rows)) / 2
+	case VerticalAlign_Bottom:
+		rowsTop += contentBox.Height() - len(rows)
+	}
 
-		// Check if we are out of bounds
-		if top < bounds.top || top >= bounds.bottom {
-			continue
+	// Only the rows inside the bounds are drawn
+	first := max(0, bounds.top-rowsTop)
+	last := min(len(rows), bounds.bottom-rowsTop)
+	for i := first; i < last; i++ {
+		top := rowsTop + i
+		row := rows[i]
+
+		// Align the row horizontally. Rows wider than the Component start at
+		// its left edge.
+		left := contentBox.left
+		if c.textAlign != TextAlign_Left {
+			space := max(0, contentBox.Width()-c.content.rowWidth(row))
+			if c.textAlign == TextAlign_Center {
+				space /= 2
+			}
+			left += space
 		}
-
-		// Position the cursor at the location of the current row
-		builder.WriteString(fmt.Sprintf("\033[%d;%dH", top+1, max(bounds.left, c.box.left)+1))
-
-		// Simple handler for blank content
-		if isBlank {
-			if blankLine == "" {
-				blankLine = c.blankLine(width)
-				c.clipWithBounds(&blankLine, &bounds)
-				if c.colorFunc != nil {
-					blankLine = c.colorFunc(blankLine)
+		put := func(content string, width int, style Style) {
+			// Characters that are only partly inside the bounds are left blank
+			if width > 0 && left >= bounds.left && left+width <= min(bounds.right, contentBox.right) {
+				f.set(top, left, Cell{Content: content, Width: width, Style: style})
+				for col := left + 1; col < left+width; col++ {
+					f.set(top, col, Cell{Style: style})
 				}
 			}
-			builder.WriteString(blankLine)
-			continue
+			left += width
 		}
-
-		// We will print a substring of c.content from a:b
-		b = a + width
-
-		// Print content if it exists, and print blank space where there isn't content
-		var result string
-		if a < contentLen {
-			var nBlanks int // The number of blank characters to append to the result
-
-			// Get the section of content that should be rendered on this line
-			substr := c.content.displaySubstring(a, min(contentLen, b))
-
-			// Handle newlines by splitting the substr and rendering the remainder on the next iteration
-			newlineIndex := strings.Index(substr, "\n")
-			if newlineIndex != -1 {
-				result = substr[:newlineIndex]
-				nBlanks = b - a - newlineIndex
-				a += newlineIndex + 1
-			} else {
-				result = substr
-				nBlanks = b - contentLen
-				a = b
-			}
-
-			// If we know where we already rendered blank space, update nBlanks accoringly
-			if c.firstBlankColumns != nil && row < len(c.firstBlankColumns) {
-				if c.firstBlankColumns[row] <= len(result) {
-					nBlanks = 0
-				} else {
-					nBlanks = c.firstBlankColumns[row] - len(result)
+		putGrapheme := func(g grapheme) {
+			style := style.merge(c.content.styleAt(g.start))
+			if isTab(value, g) {
+				// Tabs are expanded to blanks up to the next tab stop
+				for range g.width {
+					put(BLANK_CHAR, 1, style)
 				}
+				return
 			}
-			firstBlankColumns[row] = len(result)
-
-			// Clear the remainder of the current line
-			if nBlanks > 0 {
-				result += strings.Repeat(BLANK_CHAR, nBlanks)
-			}
-		} else {
-			// If we are done rendering content, save the first blank row
-			if firstBlankRow == -1 {
-				firstBlankRow = row
-			}
-			// If we are beyond the first blank row from the previous render, skip the rest of this render
-			if c.firstBlankRow != -1 && c.firstBlankRow <= row {
-				goto Output
-			}
-
-			// Clear the current line
-			if blankLine == "" {
-				blankLine = c.blankLine(width)
-			}
-			result = blankLine
+			put(value[g.start:g.end], g.width, style)
 		}
-
-		c.clipWithBounds(&result, &bounds)
-		if c.colorFunc != nil {
-			result = c.colorFunc(result)
+		for _, g := range gs[row.start:row.cut] {
+			putGrapheme(g)
+		}
+		if row.cut < row.resume {
+			put(ELLIPSIS_CHAR, StringWidth(ELLIPSIS_CHAR), style)
+		}
+		for _, g := range gs[row.resume:row.end] {
+			putGrapheme(g)
 		}
-
-		builder.WriteString(result)
-	}
-
-Output:
-	// Update the locations of blank space from this render
-	if isBlank {
-		c.firstBlankRow = 0
-	} else {
-		c.firstBlankRow = firstBlankRow
 	}
-	c.firstBlankColumns = firstBlankColumns
-
-	// Output to stdout
-	fmt.Print(builder.String())
-
 }
@@ -90,8 +90,8 @@ func NewBorders() *Borders {
 	b.left.SetLength(1)
 	b.right.SetLength(1)
 
-	// By default, don't display the title
-	b.title.SetGrow(0)
+	// The title is as long as its content, so it isn't displayed by default
+	b.title.SetAutoLength(true)
 
 	// Horizontally lay out the left border, the midSection, and the right border
 	b.Outer.AddChild(b.left)
@@ -155,7 +155,6 @@ func (b *Borders) SetTitle(title string) {
 	defer b.mu.Unlock()
 
 	b.title.SetContent(title)
-	b.title.SetLength(len(title))
 }
 
 // Set whether the title is shown on the top border or the bottom border.
@@ -166,10 +165,8 @@ func (b *Borders) SetTitleIsOnBottom(titleIsOnBottom bool) {
 	if b.titleIsOnBottom != titleIsOnBottom {
 		// Swap the top/bottom Components and update their parent's children list
 		b.top, b.bottom = b.bottom, b.top
-		b.midSection.RemoveAllChildren()
-		b.midSection.AddChild(b.top)
-		b.midSection.AddChild(b.innerWrapper)
-		b.midSection.AddChild(b.bottom)
+		b.midSection.MoveChild(b.top, 0)
+		b.midSection.MoveChild(b.bottom, 2)
 	}
 	b.titleIsOnBottom = titleIsOnBottom
 	b.updateContentFuncs()
@@ -184,23 +181,33 @@ func (b *Borders) SetBorderSymbols(symbols *BordersSymbols) {
 	b.updateContentFuncs()
 }
 
+// Set the style of the borders. See [flextui.Component.SetStyle].
+func (b *Borders) SetStyle(style flextui.Style) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.top.SetStyle(style)
+	b.bottom.SetStyle(style)
+	b.left.SetStyle(style)
+	b.right.SetStyle(style)
+	b.titleLeft.SetStyle(style)
+	b.titleRight.SetStyle(style)
+}
+
 // Set the ColorFunc for the borders. See [flextui.Component.SetColorFunc].
 func (b *Borders) SetColorFunc(colorFunc func(a ...any) string) {
+	b.SetStyle(flextui.StyleFromColorFunc(colorFunc))
+}
+
+// Set the style of the title. See [flextui.Component.SetStyle].
+func (b *Borders) SetTitleStyle(style flextui.Style) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	b.top.SetColorFunc(colorFunc)
-	b.bottom.SetColorFunc(colorFunc)
-	b.left.SetColorFunc(colorFunc)
-	b.right.SetColorFunc(colorFunc)
-	b.titleLeft.SetColorFunc(colorFunc)
-	b.titleRight.SetColorFunc(colorFunc)
+	b.title.SetStyle(style)
 }
 
 // Set the ColorFunc for the title. See [flextui.Component.SetColorFunc].
 func (b *Borders) SetTitleColorFunc(colorFunc func(a ...any) string) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	b.title.SetColorFunc(colorFunc)
+	b.SetTitleStyle(flextui.StyleFromColorFunc(colorFunc))
 }
@@ -0,0 +1,35 @@
+package components_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/components"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestBordersWithMenu(t *testing.T) {
+	screen, vt := flextuitest.NewScreen(30, 8)
+
+	sidebar := components.NewBorders()
+	sidebar.SetTitle(" Menu ")
+	sidebar.SetBorderSymbols(components.BordersSymbols_Double)
+	sidebar.SetStyle(flextui.Style{Fg: flextui.Color_Red})
+	sidebar.Outer.SetLength(16)
+	sidebar.Inner.SetIsVertical(true)
+	screen.AddChild(sidebar.Outer)
+
+	menu := components.NewMenu([]string{"First item", "Second item", "A very long third item"})
+	menu.SetSelectedStyle(flextui.Style{Fg: flextui.Color_Black, Bg: flextui.Color_Yellow})
+	menu.AddSelection(1)
+	sidebar.Inner.AddChild(menu.Outer)
+
+	main := components.NewBorders()
+	main.SetTitle(" Main ")
+	main.SetTitleIsOnBottom(true)
+	main.Inner.SetContent("Hello")
+	screen.AddChild(main.Outer)
+
+	flextuitest.AssertSnapshot(t, "borders_with_menu", screen, vt)
+	flextuitest.AssertLayout(t, screen)
+}
@@ -19,9 +19,14 @@ func NewInput() *Input {
 	input.Outer = flextui.NewComponent()
 	input.Outer.SetIsVertical(true)
 
+	// Content that doesn't fit is truncated at the start, so that the end
+	// where the cursor is stays visible, and the last column is left for the
+	// cursor
 	input.content = flextui.NewComponent()
 	input.content.SetLength(1)
 	input.content.SetContent("")
+	input.content.SetWrapMode(flextui.WrapMode_EllipsisStart)
+	input.content.SetPadding(flextui.Spacing{Right: 1})
 	input.Outer.AddChild(input.content)
 
 	cursorListener := func(c *flextui.Component) {
@@ -29,26 +34,9 @@ func NewInput() *Input {
 	}
 	input.content.AddEventListener(flextui.Event_LayoutUpdated, &cursorListener)
 
-	scrollListener := func(c *flextui.Component) {
-		input.updateScrollPos()
-	}
-	input.Outer.AddEventListener(flextui.Event_LayoutUpdated, &scrollListener)
-
 	return &input
 }
 
-func (c *Input) updateScrollPos() {
-	content := *c.content.Content()
-	boxWidth := c.Outer.Box().Width()
-	if boxWidth > 0 && len(content) >= boxWidth {
-		c.content.Scroll.Left = len(content) - boxWidth + 1
-		c.content.UpdateLayout()
-	} else if c.content.Scroll.Left != 0 {
-		c.content.Scroll.Left = 0
-		c.content.UpdateLayout()
-	}
-}
-
 func (c *Input) Content() string {
 	return *c.content.Content()
 }
@@ -58,18 +46,22 @@ func (c *Input) SetContent(content string) {
 	defer c.mu.Unlock()
 
 	c.content.SetContent(content)
-	c.updateScrollPos()
 }
 
-func (c *Input) SetColorFunc(colorFunc func(a ...any) string) {
+func (c *Input) SetStyle(style flextui.Style) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.content.SetColorFunc(colorFunc)
+	c.content.SetStyle(style)
+}
+
+func (c *Input) SetColorFunc(colorFunc func(a ...any) string) {
+	c.SetStyle(flextui.StyleFromColorFunc(colorFunc))
 }
 
 func (c *Input) UpdateCursorPos() {
 	if flextui.CursorOwner == c.Outer {
-		flextui.CursorTo(c.content.Box().Top()+1, min(c.Outer.Box().Right(), c.content.Box().Left()+len(*c.content.Content())+1))
+		width := min(flextui.StringWidth(*c.content.Content()), c.content.Box().Width()-1)
+		c.Outer.CursorTo(c.content.Box().Top()+1, min(c.Outer.Box().Right(), c.content.Box().Left()+width+1))
 	}
 }
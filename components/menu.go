@@ -11,8 +11,8 @@ type Menu struct {
 
 	selectedIndices map[int]struct{}
 
-	colorFunc         func(a ...any) string
-	selectedColorFunc func(a ...any) string
+	style         flextui.Style
+	selectedStyle flextui.Style
 
 	renderQueue map[*flextui.Component]struct{}
 
@@ -28,16 +28,16 @@ func NewMenu(items []string) *Menu {
 
 	m.Outer = flextui.NewComponent()
 	m.Outer.SetIsVertical(true)
+	m.Outer.SetAutoLength(true)
 
 	for _, item := range items {
 		c := flextui.NewComponent()
 		c.SetContent(item)
 		c.SetLength(1)
+		c.SetWrapMode(flextui.WrapMode_EllipsisEnd)
 		m.Outer.AddChild(c)
 	}
 
-	m.Outer.SetLength(len(items))
-
 	return &m
 }
 
@@ -54,58 +54,89 @@ func (m *Menu) SetIsVertical(isVertical bool) {
 	defer m.mu.Unlock()
 
 	if m.Outer.IsVertical() != isVertical {
-		items := m.Outer.Children()
-		if isVertical {
-			for _, c := range items {
+		m.Outer.SetIsVertical(isVertical)
+		for _, c := range m.Outer.Children() {
+			if isVertical {
 				c.SetLength(1)
+			} else {
+				c.SetAutoLength(true)
 			}
-			m.Outer.SetLength(len(items))
-		} else {
-			sum := 0
-			for _, c := range items {
-				l := len(*c.Content())
-				c.SetLength(l)
-				sum += l
-			}
-			m.Outer.SetLength(sum)
 		}
-		m.Outer.SetIsVertical(isVertical)
 	}
 }
 
-func (m *Menu) SetColorFunc(colorFunc func(a ...any) string) {
+// Set whether items that don't fit in the Menu wrap onto a new line (or
+// column, if the Menu is vertical) instead of overflowing it.
+func (m *Menu) SetWrap(wrap bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Outer.SetWrap(wrap)
+}
+
+// Set how items that are wider than the Menu are fit into it. By default,
+// the end of the item is replaced with an ellipsis
+// (flextui.WrapMode_EllipsisEnd).
+func (m *Menu) SetWrapMode(wrapMode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.Outer.Children() {
+		c.SetWrapMode(wrapMode)
+	}
+}
+
+// Set the number of blank cells between items.
+func (m *Menu) SetGap(gap int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Outer.SetGap(gap)
+}
+
+// Set the style of items that aren't selected.
+func (m *Menu) SetStyle(style flextui.Style) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.colorFunc = colorFunc
+	m.style = style
 	for i, c := range m.Outer.Children() {
 		if _, exists := m.selectedIndices[i]; !exists {
-			c.SetColorFunc(colorFunc)
+			c.SetStyle(style)
 			m.enqueue(c)
 		}
 	}
 }
 
-func (m *Menu) SetSelectedColorFunc(colorFunc func(a ...any) string) {
+func (m *Menu) SetColorFunc(colorFunc func(a ...any) string) {
+	m.SetStyle(flextui.StyleFromColorFunc(colorFunc))
+}
+
+// Set the style of selected items.
+func (m *Menu) SetSelectedStyle(style flextui.Style) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.selectedColorFunc = colorFunc
+	m.selectedStyle = style
 	for i, c := range m.Outer.Children() {
 		if _, exists := m.selectedIndices[i]; exists {
-			c.SetColorFunc(colorFunc)
+			c.SetStyle(style)
 			m.enqueue(c)
 		}
 	}
 }
 
+func (m *Menu) SetSelectedColorFunc(colorFunc func(a ...any) string) {
+	m.SetSelectedStyle(flextui.StyleFromColorFunc(colorFunc))
+}
+
 func (m *Menu) AddSelection(index int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.selectedIndices[index] = struct{}{}
 	c := m.Outer.Children()[index]
-	c.SetColorFunc(m.selectedColorFunc)
+	c.SetStyle(m.selectedStyle)
 	m.enqueue(c)
 }
 
@@ -115,7 +146,7 @@ func (m *Menu) RemoveSelection(index int) {
 
 	delete(m.selectedIndices, index)
 	c := m.Outer.Children()[index]
-	c.SetColorFunc(m.colorFunc)
+	c.SetStyle(m.style)
 	m.enqueue(c)
 }
 
@@ -125,7 +156,7 @@ func (m *Menu) RemoveAllSelections() {
 
 	for i := range m.selectedIndices {
 		c := m.Outer.Children()[i]
-		c.SetColorFunc(m.colorFunc)
+		c.SetStyle(m.style)
 		m.enqueue(c)
 	}
 	m.selectedIndices = make(map[int]struct{})
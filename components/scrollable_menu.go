@@ -24,27 +24,28 @@ func NewScrollableMenu(items []string) *ScrollableMenu {
 
 	sm.scrollToSelectedItem = func(parent *flextui.Component) {
 		if sm.Outer.IsVertical() {
-			if sm.selectedItem >= sm.Menu.Outer.Scroll.Top+parent.Box().Height() {
-				sm.Menu.Outer.Scroll.Top = sm.selectedItem - parent.Box().Height() + 1
+			top := sm.selectedItem * (1 + sm.Menu.Outer.Gap())
+			if top >= sm.Menu.Outer.Scroll.Top+parent.Box().Height() {
+				sm.Menu.Outer.Scroll.Top = top - parent.Box().Height() + 1
 				sm.Menu.Outer.UpdateLayout()
 				sm.needsRender = true
-			} else if sm.selectedItem < sm.Menu.Outer.Scroll.Top {
-				sm.Menu.Outer.Scroll.Top = sm.selectedItem
+			} else if top < sm.Menu.Outer.Scroll.Top {
+				sm.Menu.Outer.Scroll.Top = top
 				sm.Menu.Outer.UpdateLayout()
 				sm.needsRender = true
 			}
 		} else {
 			items := sm.Menu.Outer.Children()
-			width := 0
+			width := sm.Menu.Outer.Gap() * sm.selectedItem
 			for i := 0; i <= sm.selectedItem; i++ {
-				width += items[i].Length()
+				width += items[i].Box().Width()
 			}
 			if width >= sm.Menu.Outer.Scroll.Left+parent.Box().Width() {
 				sm.Menu.Outer.Scroll.Left = width - parent.Box().Width()
 				sm.Menu.Outer.UpdateLayout()
 				sm.needsRender = true
-			} else if width-items[sm.selectedItem].Length() < sm.Menu.Outer.Scroll.Left {
-				sm.Menu.Outer.Scroll.Left = width - items[sm.selectedItem].Length()
+			} else if width-items[sm.selectedItem].Box().Width() < sm.Menu.Outer.Scroll.Left {
+				sm.Menu.Outer.Scroll.Left = width - items[sm.selectedItem].Box().Width()
 				sm.Menu.Outer.UpdateLayout()
 				sm.needsRender = true
 			}
@@ -80,7 +81,10 @@ func (sm *ScrollableMenu) SetIsVertical(isVertical bool) {
 
 	sm.Outer.SetIsVertical(isVertical)
 	sm.Menu.SetIsVertical(isVertical)
-	sm.Outer.SetLength(sm.Menu.Outer.Length())
+	sm.Outer.SetAutoLength(!isVertical)
+
+	// Scroll positions along the previous orientation no longer apply
+	sm.Menu.Outer.Scroll = flextui.Scroll{}
 }
 
 func (sm *ScrollableMenu) RenderChanges() {
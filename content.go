@@ -1,47 +1,271 @@
 package flextui
 
-import "unicode/utf8"
+import (
+	"sort"
+	"strings"
+)
 
 type content struct {
 	value      *string
 	updateFunc func(*Box) string
-	hasUnicode bool
+	wrapMode   int
+	styles     []styleRun // The styles of parts of the value, in order
+
+	// An index of the value, built when it is set, so that rows can be found
+	// and drawn without scanning the whole value
+	graphemes []grapheme // The grapheme clusters of the value
+	maxWidth  int        // The width of the longest line, in cells
+	rowsWidth int        // The width that rows were last wrapped to
+	rows      []contentRow
+}
+
+// A Style that applies to the value of a content from a byte offset up to the
+// start of the next run.
+type styleRun struct {
+	start int
+	style Style
 }
 
+// Set the value of the content. Escape sequences in the value aren't
+// displayed, and the SGR sequences among them style the text after them.
 func (c *content) setValue(value *string) {
-	c.hasUnicode = len(*value) != utf8.RuneCountInString(*value)
+	if strings.IndexByte(*value, '\033') >= 0 {
+		text, styles := parseEscapes(*value)
+		c.setText(&text, styles)
+	} else {
+		c.setText(value, nil)
+	}
+}
+
+// Set the value to the text of the spans, with their styles. SGR sequences in
+// the spans style their text on top of the spans' styles.
+func (c *content) setSpans(spans []Span) {
+	var builder strings.Builder
+	var styles []styleRun
+	for _, span := range spans {
+		if span.Text == "" {
+			continue
+		}
+		text, spanStyles := parseEscapes(span.Text)
+		styles = append(styles, styleRun{start: builder.Len(), style: span.Style})
+		for _, run := range spanStyles {
+			styles = append(styles, styleRun{start: builder.Len() + run.start, style: span.Style.merge(run.style)})
+		}
+		builder.WriteString(text)
+	}
+	value := builder.String()
+	c.setText(&value, styles)
+}
+
+// Set the value to plain text with the given styles, and index it.
+func (c *content) setText(value *string, styles []styleRun) {
 	c.value = value
+	c.styles = styles
+	c.graphemes = graphemes(*value)
+	c.rows = nil
+
+	c.maxWidth = 0
+	lineWidth := 0
+	for _, g := range c.graphemes {
+		if isLineBreak(*value, g) {
+			lineWidth = 0
+			continue
+		}
+		lineWidth += g.width
+		c.maxWidth = max(c.maxWidth, lineWidth)
+	}
+}
+
+// Returns the style of the part of the value at a byte offset.
+func (c *content) styleAt(i int) Style {
+	n := sort.Search(len(c.styles), func(j int) bool { return c.styles[j].start > i })
+	if n == 0 {
+		return Style{}
+	}
+	return c.styles[n-1].style
+}
+
+func (c *content) setWrapMode(wrapMode int) {
+	c.wrapMode = wrapMode
+	c.rows = nil
+}
+
+// Returns the rows of the content fit to the given width. The rows are kept
+// until the value, the wrap mode or the width changes. Must be called while
+// holding the lock of the Component that the content belongs to.
+func (c *content) wrap(width int) []contentRow {
+	if c.rows == nil || c.rowsWidth != width {
+		c.rows = wrapRows(*c.value, c.graphemes, width, c.wrapMode)
+		c.rowsWidth = width
+	}
+	return c.rows
+}
+
+// A row of content, as a range of indices of its grapheme clusters. In
+// truncated rows, the grapheme clusters from cut to resume are replaced with
+// an ellipsis.
+type contentRow struct {
+	start, end  int
+	cut, resume int
+}
+
+// Returns whether a grapheme cluster of s is a line break.
+func isLineBreak(s string, g grapheme) bool {
+	return s[g.end-1] == '\n'
+}
+
+// Returns whether a grapheme cluster of s is a space that words can be
+// wrapped at.
+func isSpace(s string, g grapheme) bool {
+	return g.end-g.start == 1 && (s[g.start] == ' ' || s[g.start] == '\t')
 }
 
-func (c *content) displayLen() int {
-	if c.hasUnicode {
-		return utf8.RuneCountInString(*c.value)
+// Split the grapheme clusters of s into rows that fit in the given width,
+// according to the wrap mode. Each line of s starts a new row, and line
+// breaks are not part of any row.
+func wrapRows(s string, gs []grapheme, width, wrapMode int) []contentRow {
+	var rows []contentRow
+	if width <= 0 {
+		return rows
 	}
-	return len(*c.value)
+	for i := 0; i < len(gs); i++ {
+		start := i
+		for i < len(gs) && !isLineBreak(s, gs[i]) {
+			i++
+		}
+
+		switch wrapMode {
+		case WrapMode_Char, WrapMode_Word:
+			rows = wrapLine(s, gs, start, i, width, wrapMode == WrapMode_Word, rows)
+		case WrapMode_None:
+			rows = append(rows, contentRow{start: start, end: i, cut: i, resume: i})
+		default:
+			rows = append(rows, truncateLine(gs, start, i, width, wrapMode))
+		}
+	}
+	return rows
 }
 
-func (c *content) realIndex(displayIndex int) int {
-	if c.hasUnicode {
-		if displayIndex == 0 {
-			return 0
+// Append the rows that a line of grapheme clusters from start to end wraps
+// into. A row ends before the next grapheme cluster that doesn't fit, or with
+// word wrapping, before the last word that doesn't fit if the row has a space
+// to break at. Spaces at the ends of word wrapped rows are left out. A
+// grapheme cluster wider than the whole width gets a row of its own.
+func wrapLine(s string, gs []grapheme, start, end, width int, words bool, rows []contentRow) []contentRow {
+	i := start
+	for {
+		rowStart := i
+		rowWidth := 0
+		lastSpace := -1
+		for i < end && rowWidth+gs[i].width <= width {
+			if words && isSpace(s, gs[i]) {
+				lastSpace = i
+			}
+			rowWidth += gs[i].width
+			i++
 		}
-		runeCount := utf8.RuneCountInString(*c.value)
-		if runeCount < displayIndex {
-			return len(*c.value) + (displayIndex - runeCount)
+		if i == rowStart && i < end {
+			i++
 		}
-		for i := len(*c.value); i > 0; i-- {
-			runeCount = utf8.RuneCountInString((*c.value)[:i])
-			if runeCount == displayIndex {
-				return i
+
+		rowEnd := i
+		if words && i < end {
+			if !isSpace(s, gs[i]) && lastSpace > rowStart {
+				rowEnd = lastSpace
+				i = lastSpace
+			}
+			for rowEnd > rowStart && isSpace(s, gs[rowEnd-1]) {
+				rowEnd--
 			}
+			for i < end && isSpace(s, gs[i]) {
+				i++
+			}
+		}
+		rows = append(rows, contentRow{start: rowStart, end: rowEnd, cut: rowEnd, resume: rowEnd})
+
+		if i >= end {
+			return rows
+		}
+	}
+}
+
+// Returns the row of a line of grapheme clusters from start to end, with
+// some of them replaced by an ellipsis if the line doesn't fit in the width.
+func truncateLine(gs []grapheme, start, end, width, wrapMode int) contentRow {
+	row := contentRow{start: start, end: end, cut: end, resume: end}
+	lineWidth := 0
+	for _, g := range gs[start:end] {
+		lineWidth += g.width
+	}
+	if lineWidth <= width {
+		return row
+	}
+
+	// Returns the index after the grapheme clusters from start that fit in
+	// the given width
+	head := func(width int) int {
+		i := start
+		for i < end && gs[i].width <= width {
+			width -= gs[i].width
+			i++
+		}
+		return i
+	}
+	// Returns the index of the first of the grapheme clusters before end that
+	// fit in the given width
+	tail := func(width int) int {
+		i := end
+		for i > start && gs[i-1].width <= width {
+			width -= gs[i-1].width
+			i--
+		}
+		return i
+	}
+
+	available := width - StringWidth(ELLIPSIS_CHAR)
+	switch wrapMode {
+	case WrapMode_EllipsisEnd:
+		row.cut = head(available)
+	case WrapMode_EllipsisStart:
+		row.cut = start
+		row.resume = tail(available)
+	case WrapMode_EllipsisMiddle:
+		row.cut = head((available + 1) / 2)
+		headWidth := 0
+		for _, g := range gs[start:row.cut] {
+			headWidth += g.width
 		}
+		row.resume = tail(available - headWidth)
 	}
-	return displayIndex
+	return row
 }
 
-func (c *content) displaySubstring(a, b int) string {
-	if c.hasUnicode {
-		return (*c.value)[c.realIndex(a):c.realIndex(b)]
+// Returns the number of cells a row of content occupies.
+func (c *content) rowWidth(row contentRow) int {
+	width := 0
+	for _, g := range c.graphemes[row.start:row.cut] {
+		width += g.width
+	}
+	if row.cut < row.resume {
+		width += StringWidth(ELLIPSIS_CHAR)
+	}
+	for _, g := range c.graphemes[row.resume:row.end] {
+		width += g.width
+	}
+	return width
+}
+
+// Returns the number of cells the content needs along an axis: the width of
+// its longest line horizontally, or the number of rows it fills when wrapped
+// to the given width vertically. Parents measure their children without
+// holding the children's locks, so this doesn't use the cache of rows that
+// wrap() keeps for drawing.
+func (c *content) measure(isVertical bool, width int) int {
+	if c.value == nil || *c.value == "" {
+		return 0
+	}
+	if isVertical {
+		return len(wrapRows(*c.value, c.graphemes, width, c.wrapMode))
 	}
-	return (*c.value)[a:b]
+	return c.maxWidth
 }
@@ -0,0 +1,104 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestWrapMode(t *testing.T) {
+	const text = "hello wide world\nab"
+	tests := []struct {
+		name     string
+		wrapMode int
+		want     []string
+	}{
+		{"char", flextui.WrapMode_Char, []string{"hello wi", "de world", "ab      "}},
+		{"word", flextui.WrapMode_Word, []string{"hello   ", "wide    ", "world   ", "ab      "}},
+		{"none", flextui.WrapMode_None, []string{"hello wi", "ab      ", "        ", "        "}},
+		{"ellipsis end", flextui.WrapMode_EllipsisEnd, []string{"hello w…", "ab      ", "        ", "        "}},
+		{"ellipsis middle", flextui.WrapMode_EllipsisMiddle, []string{"hell…rld", "ab      ", "        ", "        "}},
+		{"ellipsis start", flextui.WrapMode_EllipsisStart, []string{"…e world", "ab      ", "        ", "        "}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, vt := flextuitest.NewScreen(8, len(test.want))
+			root.SetContent(text)
+			root.SetWrapMode(test.wrapMode)
+			assertLines(t, renderLines(root, vt), test.want...)
+		})
+	}
+}
+
+func TestWordWrapLongWord(t *testing.T) {
+	// Words longer than the width are broken at the last character that fits
+	root, vt := flextuitest.NewScreen(4, 3)
+	root.SetContent("abcdefg hi")
+	root.SetWrapMode(flextui.WrapMode_Word)
+	assertLines(t, renderLines(root, vt), "abcd", "efg ", "hi  ")
+}
+
+func TestEllipsisWideCharacters(t *testing.T) {
+	// Wide characters that don't fit next to the ellipsis are left out
+	root, vt := flextuitest.NewScreen(6, 1)
+	root.SetContent("日本語です")
+	root.SetWrapMode(flextui.WrapMode_EllipsisEnd)
+	renderLines(root, vt)
+	for col, want := range []string{"日", "", "本", "", "…", " "} {
+		if got := vt.Cell(0, col).Content; got != want {
+			t.Errorf("Cell %d is %q, want %q", col, got, want)
+		}
+	}
+}
+
+func TestTextAlign(t *testing.T) {
+	tests := []struct {
+		name      string
+		textAlign int
+		want      []string
+	}{
+		{"left", flextui.TextAlign_Left, []string{" ab      ", " abcd    ", " abcdefgh"}},
+		{"center", flextui.TextAlign_Center, []string{"    ab   ", "   abcd  ", " abcdefgh"}},
+		{"right", flextui.TextAlign_Right, []string{"       ab", "     abcd", " abcdefgh"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, vt := flextuitest.NewScreen(9, 3)
+			root.SetPadding(flextui.Spacing{Left: 1})
+			root.SetContent("ab\nabcd\nabcdefghij")
+			root.SetWrapMode(flextui.WrapMode_None)
+			root.SetTextAlign(test.textAlign)
+			assertLines(t, renderLines(root, vt), test.want...)
+		})
+	}
+}
+
+func TestVerticalAlign(t *testing.T) {
+	tests := []struct {
+		name          string
+		verticalAlign int
+		want          []string
+	}{
+		{"top", flextui.VerticalAlign_Top, []string{"ab", "cd", "  ", "  ", "  "}},
+		{"middle", flextui.VerticalAlign_Middle, []string{"  ", "ab", "cd", "  ", "  "}},
+		{"bottom", flextui.VerticalAlign_Bottom, []string{"  ", "  ", "  ", "ab", "cd"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, vt := flextuitest.NewScreen(2, 5)
+			root.SetContent("abcd")
+			root.SetVerticalAlign(test.verticalAlign)
+			assertLines(t, renderLines(root, vt), test.want...)
+		})
+	}
+}
+
+func TestVerticalAlignOverflow(t *testing.T) {
+	// Content with more rows than fit is drawn from the top regardless of the
+	// vertical alignment
+	root, vt := flextuitest.NewScreen(2, 2)
+	root.SetContent("abcdef")
+	root.SetVerticalAlign(flextui.VerticalAlign_Bottom)
+	assertLines(t, renderLines(root, vt), "ab", "cd")
+}
@@ -0,0 +1,35 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestCursorIsPerTerminal(t *testing.T) {
+	a, aTerminal := flextuitest.NewScreen(10, 5)
+	a.SetContent("a")
+	b, bTerminal := flextuitest.NewScreen(10, 5)
+	b.SetContent("b")
+
+	a.ShowCursor()
+	a.CursorTo(3, 4)
+	b.HideCursor()
+	flextuitest.Render(a)
+	flextuitest.Render(b)
+
+	if row, col := aTerminal.Cursor(); !aTerminal.CursorVisible() || row != 2 || col != 3 {
+		t.Errorf("Cursor of the first terminal is at (%d, %d), visible: %t, want (2, 3) and visible", row, col, aTerminal.CursorVisible())
+	}
+	if bTerminal.CursorVisible() {
+		t.Errorf("Cursor of the second terminal is visible after rendering")
+	}
+
+	// Terminals whose cursor was never shown keep it hidden after rendering
+	c, cTerminal := flextuitest.NewScreen(10, 5)
+	c.SetContent("c")
+	flextuitest.Render(c)
+	if cTerminal.CursorVisible() {
+		t.Errorf("Cursor of a new terminal is visible after rendering")
+	}
+}
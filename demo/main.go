@@ -54,6 +54,7 @@ func main() {
 	tui.Screen.AddChild(mainArea.Outer)
 
 	mainContent := tui.NewComponent()
+	mainContent.SetPadding(tui.Spacing{Left: 1, Right: 1})
 	mainArea.Inner.AddChild(mainContent)
 
 	inputArea := tui.NewComponent()
@@ -72,15 +73,15 @@ func main() {
 
 	themesMenuArea := tui.NewComponent()
 	themesMenuArea.SetLength(1)
+	themesMenuArea.SetJustify(tui.Justify_Center)
 	mainArea.Inner.AddChild(themesMenuArea)
 
-	themesMenu := components.NewMenu([]string{" [1] Dark Theme ", " [2] Light Theme ", " [3] Epic Theme "})
+	themesMenu := components.NewMenu([]string{"[1] Dark Theme", "[2] Light Theme", "[3] Epic Theme"})
 	themesMenu.SetIsVertical(false)
+	themesMenu.SetGap(2)
 	themesMenu.SetSelectedColorFunc(color.New(color.Bold).Add(color.FgMagenta).SprintFunc())
 	themesMenu.AddSelection(0)
-	themesMenuArea.AddChild(tui.NewComponent())
 	themesMenuArea.AddChild(themesMenu.Outer)
-	themesMenuArea.AddChild(tui.NewComponent())
 
 	tui.Screen.UpdateLayout()
 	tui.Screen.Render()
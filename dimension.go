@@ -0,0 +1,123 @@
+package flextui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Dimension is a length made of a fraction of the parent Component's length
+// plus a number of cells, like the CSS expression calc(50% - 2ch). See
+// [Component.SetLengthDimension].
+type Dimension struct {
+	Ratio float64 // The fraction of the parent's length, e.g. 0.5 for 50%
+	Cells int     // The number of cells added to the fraction
+}
+
+// Returns a Dimension of a fixed number of cells.
+func Cells(cells int) Dimension {
+	return Dimension{Cells: cells}
+}
+
+// Returns a Dimension that is a percentage of the parent's length.
+func Percent(percent float64) Dimension {
+	return Dimension{Ratio: percent / 100}
+}
+
+// Returns a Dimension that is a fraction of the parent's length, e.g.
+// Fraction(1, 3) for a third.
+func Fraction(numerator, denominator int) Dimension {
+	return Dimension{Ratio: float64(numerator) / float64(denominator)}
+}
+
+// Returns this Dimension with a number of cells added.
+func (d Dimension) Plus(cells int) Dimension {
+	d.Cells += cells
+	return d
+}
+
+// Returns this Dimension with a number of cells subtracted.
+func (d Dimension) Minus(cells int) Dimension {
+	d.Cells -= cells
+	return d
+}
+
+// Returns the length of this Dimension in a parent of the given length. The
+// length is never negative.
+func (d Dimension) resolve(parentLength int) float64 {
+	return max(0, d.Ratio*float64(parentLength)+float64(d.Cells))
+}
+
+func (d Dimension) String() string {
+	var b strings.Builder
+	if d.Ratio != 0 {
+		b.WriteString(strconv.FormatFloat(d.Ratio*100, 'f', -1, 64) + "%")
+		if d.Cells > 0 {
+			b.WriteString("+")
+		}
+	}
+	if d.Cells != 0 || d.Ratio == 0 {
+		b.WriteString(strconv.Itoa(d.Cells))
+	}
+	return b.String()
+}
+
+// Parses a Dimension from a sum of terms, where each term is a percentage
+// like "30%", a fraction like "1/3", or a number of cells like "2". Examples:
+// "30%", "1/3", "50% - 2", "100%-1/4+1".
+func ParseDimension(s string) (Dimension, error) {
+	var d Dimension
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return d, fmt.Errorf("invalid dimension %q: empty", s)
+	}
+
+	sign := 1.0
+	if rest[0] == '-' || rest[0] == '+' {
+		if rest[0] == '-' {
+			sign = -1
+		}
+		rest = rest[1:]
+	}
+	for {
+		// Find the end of the next term
+		end := strings.IndexAny(rest, "+-")
+		term := rest
+		if end >= 0 {
+			term = rest[:end]
+		}
+		term = strings.TrimSpace(term)
+
+		switch {
+		case strings.HasSuffix(term, "%"):
+			percent, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(term, "%")), 64)
+			if err != nil {
+				return Dimension{}, fmt.Errorf("invalid dimension %q: bad percentage %q", s, term)
+			}
+			d.Ratio += sign * percent / 100
+		case strings.Contains(term, "/"):
+			numerator, denominator, _ := strings.Cut(term, "/")
+			n, err1 := strconv.ParseFloat(strings.TrimSpace(numerator), 64)
+			m, err2 := strconv.ParseFloat(strings.TrimSpace(denominator), 64)
+			if err1 != nil || err2 != nil || m == 0 {
+				return Dimension{}, fmt.Errorf("invalid dimension %q: bad fraction %q", s, term)
+			}
+			d.Ratio += sign * n / m
+		default:
+			cells, err := strconv.Atoi(term)
+			if err != nil {
+				return Dimension{}, fmt.Errorf("invalid dimension %q: bad number of cells %q", s, term)
+			}
+			d.Cells += int(sign) * cells
+		}
+
+		if end < 0 {
+			return d, nil
+		}
+		sign = 1
+		if rest[end] == '-' {
+			sign = -1
+		}
+		rest = rest[end+1:]
+	}
+}
@@ -0,0 +1,39 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+)
+
+func TestParseDimension(t *testing.T) {
+	tests := []struct {
+		s    string
+		want flextui.Dimension
+	}{
+		{"10", flextui.Cells(10)},
+		{"-2", flextui.Cells(-2)},
+		{"+3", flextui.Cells(3)},
+		{"0%", flextui.Percent(0)},
+		{"50%", flextui.Percent(50)},
+		{"1/4", flextui.Fraction(1, 4)},
+		{"50% - 2", flextui.Percent(50).Minus(2)},
+		{"-25% + 10", flextui.Percent(-25).Plus(10)},
+	}
+	for _, test := range tests {
+		got, err := flextui.ParseDimension(test.s)
+		if err != nil {
+			t.Errorf("ParseDimension(%q) returned an error: %v", test.s, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseDimension(%q) = %+v, want %+v", test.s, got, test.want)
+		}
+	}
+
+	for _, s := range []string{"", "-", "10 +", "abc", "1/0"} {
+		if _, err := flextui.ParseDimension(s); err == nil {
+			t.Errorf("ParseDimension(%q) did not return an error", s)
+		}
+	}
+}
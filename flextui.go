@@ -2,45 +2,46 @@ package flextui
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 )
 
-// Parent of all components. Fills the entire terminal window.
+// Parent of all components. Fills the entire terminal window. By default, it is
+// bound to a Terminal that writes to [os.Stdout]. See
+// [Component.SetTerminal].
 var Screen *Component
 
 // The Component that is currently allowed to modify the cursor position.
 var CursorOwner *Component
 
-var cursorHidden bool
-var cursorRow int
-var cursorCol int
-
 func init() {
 	Screen = NewComponent()
+	Screen.SetTerminal(NewAnsiTerminal(os.Stdout))
+
+	// Like a real terminal, the Screen starts with its cursor showing
+	Screen.display.cursorVisible = true
 }
 
+// Hide the cursor of the Screen's Terminal. See [Component.HideCursor].
 func HideCursor() {
-	cursorHidden = true
-	fmt.Print("\033[?25l")
+	Screen.HideCursor()
 }
 
+// Show the cursor of the Screen's Terminal. See [Component.ShowCursor].
 func ShowCursor() {
-	cursorHidden = false
-	fmt.Print("\033[?25h")
+	Screen.ShowCursor()
 }
 
+// Move the cursor of the Screen's Terminal. See [Component.CursorTo].
 func CursorTo(row, col int) {
-	cursorRow = row
-	cursorCol = col
-	fmt.Printf("\033[%d;%dH", row, col)
+	Screen.CursorTo(row, col)
 }
 
 func Clear() {
-	fmt.Print("\033[H\033[2J")
+	Screen.Terminal().Clear()
+	Screen.display.cleared()
 }
 
 // Handles SIGINT, SIGTERM, and SIGWINCH signals.
@@ -0,0 +1,77 @@
+// Package flextuitest provides helpers for testing flextui layouts without a
+// real TTY, by rendering them into a [flextui.VirtualTerminal] and comparing
+// the result with golden files.
+package flextuitest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/computerdane/flextui"
+)
+
+// Golden files are written instead of compared when this environment variable
+// is set to 1, e.g. FLEXTUITEST_UPDATE=1 go test ./...
+const updateEnv = "FLEXTUITEST_UPDATE"
+
+// Creates a root Component bound to a new VirtualTerminal of the given size.
+// Add children to the root, then call Render() to lay them out and draw them.
+func NewScreen(width, height int) (*flextui.Component, *flextui.VirtualTerminal) {
+	vt := flextui.NewVirtualTerminal(width, height)
+	root := flextui.NewComponent()
+	root.SetTerminal(vt)
+	return root, vt
+}
+
+// Updates the layout of root and renders it to its Terminal.
+func Render(root *flextui.Component) {
+	root.UpdateLayout()
+	root.Render()
+}
+
+// Returns a snapshot of the terminal containing the plain text of every row,
+// followed by the styles of all styled cells. See
+// [flextui.VirtualTerminal.StyleString].
+func Snapshot(vt *flextui.VirtualTerminal) string {
+	snapshot := vt.String() + "\n"
+	if styles := vt.StyleString(); styles != "" {
+		snapshot += "--- styles ---\n" + styles
+	}
+	return snapshot
+}
+
+// Compares got with the contents of testdata/<name>.golden, and fails the test
+// if they differ. When tests are run with FLEXTUITEST_UPDATE=1 in the
+// environment, the golden file is written instead.
+func AssertGolden(t testing.TB, name string, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if os.Getenv(updateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Error creating golden file directory: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("Error writing golden file: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading golden file (run with %s=1 to create it): %s", updateEnv, err)
+	}
+	if string(want) != got {
+		t.Errorf("Snapshot does not match %s (run with %s=1 to accept it)\n--- want ---\n%s\n--- got ---\n%s", path, updateEnv, want, got)
+	}
+}
+
+// Renders root and compares a Snapshot of its terminal with a golden file. See
+// AssertGolden().
+func AssertSnapshot(t testing.TB, name string, root *flextui.Component, vt *flextui.VirtualTerminal) {
+	t.Helper()
+
+	Render(root)
+	AssertGolden(t, name, Snapshot(vt))
+}
@@ -0,0 +1,117 @@
+package flextuitest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/computerdane/flextui"
+)
+
+// Returns the start and end of a Box along an axis.
+func span(box *flextui.Box, isVertical bool) (int, int) {
+	if isVertical {
+		return box.Top(), box.Bottom()
+	}
+	return box.Left(), box.Right()
+}
+
+// Returns the space before and after a Component along an axis.
+func spacingSpan(s flextui.Spacing, isVertical bool) (int, int) {
+	if isVertical {
+		return s.Top, s.Bottom
+	}
+	return s.Left, s.Right
+}
+
+// Checks the invariants of the flex layout of c and all of its descendants,
+// after UpdateLayout() has been called:
+//
+//   - Consecutive children never overlap along the main axis, and are
+//     separated by at least the gap and their margins. When the children
+//     wrap, a child may instead start a new line after the previous line and
+//     the cross gap.
+//   - When the children don't wrap, a child can grow without limit and the
+//     children fit, the children, their margins and the gaps between them
+//     tile the area inside the parent's padding exactly, with no overlap and
+//     no gaps.
+//
+// Hidden Components are skipped, since they take up no space, and Components
+// that are scrolled are skipped, since scrolling moves them out of the flex
+// layout on purpose. Returns an error describing the first violation found, or
+// nil.
+func CheckLayout(c *flextui.Component) error {
+	var children []*flextui.Component
+	for _, child := range c.Children() {
+		if !child.Hidden() {
+			children = append(children, child)
+		}
+	}
+	isVertical := c.IsVertical()
+
+	// Grid layouts don't follow the flex rules
+	scrolled := len(c.GridColumns()) > 0 || len(c.GridRows()) > 0
+	for _, child := range children {
+		if child.Scroll != (flextui.Scroll{}) {
+			scrolled = true
+		}
+	}
+
+	if len(children) > 0 && !scrolled {
+		padBefore, padAfter := spacingSpan(c.Padding(), isVertical)
+		start, end := span(c.Box(), isVertical)
+		start, end = start+padBefore, max(start+padBefore, end-padAfter)
+
+		canFill := false
+		minSum := c.Gap() * (len(children) - 1)
+		pos := start
+		lineEnd := 0 // The end of the current line along the cross axis
+		for i, child := range children {
+			before, after := spacingSpan(child.Margin(), isVertical)
+			crossBefore, crossAfter := spacingSpan(child.Margin(), !isVertical)
+			childStart, childEnd := span(child.Box(), isVertical)
+			crossStart, crossEnd := span(child.Box(), !isVertical)
+			if i > 0 {
+				pos += c.Gap()
+			}
+			if childStart < pos+before {
+				if !c.Wrap() || i == 0 || crossStart-crossBefore < lineEnd+c.CrossGap() {
+					return fmt.Errorf("child %d of %s starts at %d, overlapping the previous child or gap which ends at %d", i, c.Box().ToString(), childStart, pos+before)
+				}
+				lineEnd = 0
+			}
+			pos = childEnd + after
+			lineEnd = max(lineEnd, crossEnd+crossAfter)
+
+			length := child.LengthDimension()
+			if length == (flextui.Dimension{}) && !child.AutoLength() && child.Grow() > 0 && child.MaxLength() == 0 {
+				canFill = true
+			}
+			cells := int(max(0, length.Ratio*float64(end-start)+float64(length.Cells)))
+			minSum += before + after + max(cells, child.MinLength())
+		}
+
+		if !c.Wrap() && canFill && minSum <= end-start {
+			first, _ := span(children[0].Box(), isVertical)
+			firstBefore, _ := spacingSpan(children[0].Margin(), isVertical)
+			if first-firstBefore != start || pos != end {
+				return fmt.Errorf("children of %s cover %d-%d instead of %d-%d", c.Box().ToString(), first-firstBefore, pos, start, end)
+			}
+		}
+	}
+
+	for _, child := range children {
+		if err := CheckLayout(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fails the test if CheckLayout() finds a violation in the layout of c.
+func AssertLayout(t testing.TB, c *flextui.Component) {
+	t.Helper()
+
+	if err := CheckLayout(c); err != nil {
+		t.Error(err)
+	}
+}
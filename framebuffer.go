@@ -0,0 +1,215 @@
+package flextui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// A grid of Cells that Components draw into.
+type frame struct {
+	width  int
+	height int
+	cells  []Cell
+}
+
+func newFrame(width, height int) frame {
+	f := frame{width: width, height: height, cells: make([]Cell, width*height)}
+	f.fill(blankCell)
+	return f
+}
+
+func (f *frame) fill(cell Cell) {
+	for i := range f.cells {
+		f.cells[i] = cell
+	}
+}
+
+// Returns a copy of this frame resized to the given dimensions. Cells that fit
+// in the new size are kept.
+func (f *frame) resized(width, height int) frame {
+	r := newFrame(width, height)
+	for row := 0; row < min(height, f.height); row++ {
+		copy(r.cells[row*width:row*width+min(width, f.width)], f.cells[row*f.width:])
+	}
+	return r
+}
+
+func (f *frame) set(row, col int, cell Cell) {
+	if row < 0 || row >= f.height || col < 0 || col >= f.width {
+		return
+	}
+	setCell(f.cells[row*f.width:(row+1)*f.width], col, cell)
+}
+
+// Set a cell in a row of cells. Like a terminal, overwriting part of a wide
+// character replaces the rest of it with blanks.
+func setCell(row []Cell, col int, cell Cell) {
+	old := row[col]
+	if old.Width == 0 && cell.Width != 0 {
+		// Overwriting the continuation of a wide character
+		for lead := col - 1; lead >= 0; lead-- {
+			if row[lead].Width != 0 {
+				if row[lead].Width > 1 {
+					row[lead] = Cell{Content: BLANK_CHAR, Width: 1, Style: row[lead].Style}
+				}
+				break
+			}
+			row[lead] = Cell{Content: BLANK_CHAR, Width: 1, Style: row[lead].Style}
+		}
+	}
+	if old.Width > 1 {
+		// Overwriting the start of a wide character
+		for next := col + 1; next < len(row) && next < col+old.Width && row[next].Width == 0; next++ {
+			row[next] = Cell{Content: BLANK_CHAR, Width: 1, Style: row[next].Style}
+		}
+	}
+	row[col] = cell
+}
+
+// Returns the Box covering the whole frame.
+func (f *frame) box() Box {
+	return Box{bottom: f.height, right: f.width}
+}
+
+// The rendering state of a Terminal. Components draw into the back frame, and
+// flush() writes the cells that differ from the front frame, which holds what
+// the terminal is currently showing.
+type display struct {
+	terminal Terminal
+
+	back  frame
+	front frame
+
+	// The cursor state that flush() restores after drawing. The cursor stays
+	// hidden after drawing unless it was shown with showCursor(). It has its own
+	// lock, since Components move the cursor during UpdateLayout(), which may
+	// run while rendering.
+	cursorVisible bool
+	cursorRow     int
+	cursorCol     int
+	cursorMu      sync.Mutex
+
+	// Areas that removed overlays covered, which the next Render() redraws
+	// from the root Component
+	exposed []Box
+
+	mu sync.Mutex
+}
+
+func newDisplay(terminal Terminal) *display {
+	return &display{terminal: terminal}
+}
+
+// Mark an area of the frame to be redrawn by the next Render().
+func (d *display) expose(box Box) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.exposed = append(d.exposed, box)
+}
+
+// Resize the frames to match the terminal size. If the size changed, the whole
+// terminal will be redrawn on the next flush().
+func (d *display) resize() {
+	width, height, err := d.terminal.Size()
+	if err != nil || (width == d.back.width && height == d.back.height) {
+		return
+	}
+	d.back = d.back.resized(width, height)
+	d.invalidate()
+}
+
+// Mark every cell of the terminal as unknown, so that the next flush() redraws
+// all of them.
+func (d *display) invalidate() {
+	d.front = newFrame(d.back.width, d.back.height)
+	d.front.fill(Cell{})
+}
+
+// Record that the terminal has been cleared.
+func (d *display) cleared() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.front.fill(blankCell)
+}
+
+func (d *display) hideCursor() {
+	d.cursorMu.Lock()
+	defer d.cursorMu.Unlock()
+
+	d.cursorVisible = false
+	d.terminal.HideCursor()
+}
+
+func (d *display) showCursor() {
+	d.cursorMu.Lock()
+	defer d.cursorMu.Unlock()
+
+	d.cursorVisible = true
+	d.terminal.ShowCursor()
+}
+
+func (d *display) cursorTo(row, col int) {
+	d.cursorMu.Lock()
+	defer d.cursorMu.Unlock()
+
+	d.cursorRow = row
+	d.cursorCol = col
+	d.terminal.CursorTo(row, col)
+}
+
+// Write all cells of the back frame that changed since the last flush() to the
+// terminal.
+func (d *display) flush() {
+	var builder strings.Builder
+	var style Style
+	row, col := -1, -1
+	colorProfile := terminalColorProfile(d.terminal)
+
+	for i, cell := range d.back.cells {
+		if cell == d.front.cells[i] {
+			continue
+		}
+		d.front.cells[i] = cell
+
+		// Continuation cells of wide characters are drawn by the cell before them
+		if cell.Width == 0 {
+			continue
+		}
+
+		if builder.Len() == 0 {
+			// Always hide the cursor when rendering
+			builder.WriteString("\033[?25l")
+			builder.WriteString(sgr(style))
+		}
+
+		// Only move the cursor if it isn't already in the right place
+		cellRow, cellCol := i/d.back.width, i%d.back.width
+		if cellRow != row || cellCol != col {
+			fmt.Fprintf(&builder, "\033[%d;%dH", cellRow+1, cellCol+1)
+		}
+		if cellStyle := cell.Style.convert(colorProfile); cellStyle != style {
+			builder.WriteString(sgrChange(style, cellStyle))
+			style = cellStyle
+		}
+		builder.WriteString(cell.Content)
+		row, col = cellRow, cellCol+cell.Width
+	}
+
+	if builder.Len() == 0 {
+		return
+	}
+	builder.WriteString(sgr(Style{}))
+	io.WriteString(d.terminal, builder.String())
+
+	// Show the cursor again if it was previously already showing
+	d.cursorMu.Lock()
+	defer d.cursorMu.Unlock()
+	if d.cursorVisible {
+		d.terminal.ShowCursor()
+		d.terminal.CursorTo(d.cursorRow, d.cursorCol)
+	}
+}
@@ -0,0 +1,387 @@
+package flextui
+
+// Kinds of track sizes
+const (
+	trackFixed = iota // A number of cells
+	trackFr           // A fraction of the free space
+	trackAuto         // The size of the track's content
+)
+
+type trackSize struct {
+	kind  int
+	cells int
+	fr    float64
+}
+
+// A Track is the size of a row or column of a grid layout, made of a minimum
+// and a maximum size like the CSS minmax() function. See
+// [Component.SetGridColumns].
+type Track struct {
+	min trackSize
+	max trackSize
+}
+
+// Returns a Track with a fixed number of cells.
+func TrackFixed(cells int) Track {
+	size := trackSize{kind: trackFixed, cells: max(0, cells)}
+	return Track{min: size, max: size}
+}
+
+// Returns a Track that takes a share of the space left over by the other
+// tracks, proportional to fr, like the CSS fr unit. The Track can shrink to
+// nothing if there is no space left over.
+func TrackFr(fr float64) Track {
+	return Track{min: trackSize{kind: trackFixed}, max: trackSize{kind: trackFr, fr: fr}}
+}
+
+// Returns a Track that is as large as the largest Component in it.
+func TrackAuto() Track {
+	size := trackSize{kind: trackAuto}
+	return Track{min: size, max: size}
+}
+
+// Returns a Track that is at least as large as the minimum size of min, and
+// at most as large as the maximum size of max, e.g.
+// TrackMinMax(TrackFixed(10), TrackFr(1)) or
+// TrackMinMax(TrackAuto(), TrackFixed(30)).
+func TrackMinMax(min, max Track) Track {
+	return Track{min: min.min, max: max.max}
+}
+
+// The area of a grid that a child Component is placed in, in tracks.
+type gridArea struct {
+	row, column   int
+	rows, columns int
+}
+
+// The space that a child Component needs along one axis of a grid.
+type trackItem struct {
+	start, span int
+	length      int
+}
+
+// Returns whether this Component lays out its children in a grid.
+func (c *Component) isGrid() bool {
+	return len(c.gridColumns) > 0 || len(c.gridRows) > 0
+}
+
+// Place the given children in the grid, and return their areas and the
+// number of rows and columns of the grid, including implicit rows. Children
+// with an explicit cell are placed first, and the others fill the first free
+// cells in row-major order.
+func (c *Component) gridAreas(children []*Component) ([]gridArea, int, int) {
+	numColumns := max(1, len(c.gridColumns))
+	numRows := len(c.gridRows)
+
+	areas := make([]gridArea, len(children))
+	occupied := make(map[[2]int]bool)
+	fits := func(row, column, rows, columns int) bool {
+		if column+columns > numColumns {
+			return false
+		}
+		for r := row; r < row+rows; r++ {
+			for col := column; col < column+columns; col++ {
+				if occupied[[2]int{r, col}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	place := func(i int, row, column int) {
+		area := &areas[i]
+		area.row = row
+		area.column = column
+		for r := row; r < row+area.rows; r++ {
+			for col := column; col < column+area.columns; col++ {
+				occupied[[2]int{r, col}] = true
+			}
+		}
+		numRows = max(numRows, row+area.rows)
+	}
+
+	for i, child := range children {
+		areas[i].rows = max(1, child.gridRowSpan)
+		areas[i].columns = min(max(1, child.gridColumnSpan), numColumns)
+		if child.gridRow >= 0 && child.gridColumn >= 0 {
+			place(i, child.gridRow, min(child.gridColumn, numColumns-areas[i].columns))
+		}
+	}
+
+	cursorRow, cursorColumn := 0, 0
+	for i, child := range children {
+		area := areas[i]
+		switch {
+		case child.gridRow >= 0 && child.gridColumn >= 0:
+			continue
+		case child.gridRow >= 0:
+			// Find the first free column in the row
+			column := 0
+			for column+area.columns <= numColumns && !fits(child.gridRow, column, area.rows, area.columns) {
+				column++
+			}
+			place(i, child.gridRow, min(column, numColumns-area.columns))
+		case child.gridColumn >= 0:
+			// Find the first free row in the column
+			column := min(child.gridColumn, numColumns-area.columns)
+			row := 0
+			for !fits(row, column, area.rows, area.columns) {
+				row++
+			}
+			place(i, row, column)
+		default:
+			for !fits(cursorRow, cursorColumn, area.rows, area.columns) {
+				cursorColumn++
+				if cursorColumn+area.columns > numColumns {
+					cursorRow++
+					cursorColumn = 0
+				}
+			}
+			place(i, cursorRow, cursorColumn)
+			cursorColumn += area.columns
+		}
+	}
+
+	return areas, numRows, numColumns
+}
+
+// Returns the lengths of n tracks in the available space, where the tracks
+// after the defined ones are auto tracks.
+//
+// Like CSS grid, each track starts at its minimum size, where auto tracks are
+// as large as the largest item that is only in that track. Items that span
+// several tracks, but no fr tracks, grow the auto tracks they span if they
+// don't fit. Then the free space grows the tracks with a fixed or auto maximum
+// up to their maximum, and what remains is split between fr tracks in
+// proportion to their fr, without shrinking them below their minimum size.
+func trackLengths(tracks []Track, n, gap, available int, items []trackItem) []int {
+	track := func(i int) Track {
+		if i < len(tracks) {
+			return tracks[i]
+		}
+		return TrackAuto()
+	}
+
+	base := make([]int, n)
+	limit := make([]int, n) // -1 for fr tracks, which have no limit
+	for i := range n {
+		t := track(i)
+		if t.min.kind == trackFixed {
+			base[i] = t.min.cells
+		}
+		switch t.max.kind {
+		case trackFixed:
+			limit[i] = t.max.cells
+		case trackFr:
+			limit[i] = -1
+		}
+	}
+
+	for _, item := range items {
+		if item.span == 1 {
+			t := track(item.start)
+			if t.min.kind == trackAuto {
+				base[item.start] = max(base[item.start], item.length)
+			}
+			if t.max.kind == trackAuto {
+				limit[item.start] = max(limit[item.start], item.length)
+			}
+		}
+	}
+
+	// Grow the auto tracks under items that span several tracks and don't fit.
+	// Like CSS, items that span an fr track are left to the fr tracks.
+	for _, item := range items {
+		if item.span == 1 {
+			continue
+		}
+		have := gap * (item.span - 1)
+		spansFr := false
+		var autoTracks []int
+		for i := item.start; i < item.start+item.span; i++ {
+			have += base[i]
+			if track(i).min.kind == trackAuto {
+				autoTracks = append(autoTracks, i)
+			}
+			if track(i).max.kind == trackFr {
+				spansFr = true
+			}
+		}
+		if deficit := item.length - have; deficit > 0 && len(autoTracks) > 0 && !spansFr {
+			for j, i := range autoTracks {
+				base[i] += deficit / len(autoTracks)
+				if j < deficit%len(autoTracks) {
+					base[i]++
+				}
+				if limit[i] >= 0 {
+					limit[i] = max(limit[i], base[i])
+				}
+			}
+		}
+	}
+
+	free := available - gap*max(0, n-1)
+	for i := range n {
+		if limit[i] >= 0 {
+			limit[i] = max(limit[i], base[i])
+		}
+		free -= base[i]
+	}
+
+	// Grow tracks with a limit evenly until they reach it
+	for free > 0 {
+		var growable []int
+		for i := range n {
+			if limit[i] > base[i] {
+				growable = append(growable, i)
+			}
+		}
+		if len(growable) == 0 {
+			break
+		}
+		share := max(1, free/len(growable))
+		for _, i := range growable {
+			grow := min(share, limit[i]-base[i], free)
+			base[i] += grow
+			free -= grow
+		}
+	}
+
+	// Split the rest between fr tracks. Like flex children, fr tracks whose
+	// share is smaller than their minimum size keep their minimum size, and the
+	// others split what is left.
+	flexible := make([]bool, n)
+	frSum := 0.0
+	space := float64(free)
+	for i := range n {
+		if limit[i] < 0 && track(i).max.fr > 0 {
+			flexible[i] = true
+			frSum += track(i).max.fr
+			space += float64(base[i])
+		}
+	}
+	if free <= 0 || frSum == 0 {
+		return base
+	}
+	for {
+		frozen := false
+		for i := range n {
+			if flexible[i] && space*track(i).max.fr/frSum < float64(base[i]) {
+				flexible[i] = false
+				frSum -= track(i).max.fr
+				space -= float64(base[i])
+				frozen = true
+			}
+		}
+		if !frozen || frSum == 0 {
+			break
+		}
+	}
+
+	targets := make([]float64, n)
+	var indices []int // Indices of fr tracks
+	for i := range n {
+		if flexible[i] {
+			targets[i] = space * track(i).max.fr / frSum
+			indices = append(indices, i)
+		}
+	}
+	roundLengths(base, targets, indices, int(space))
+
+	return base
+}
+
+// Returns the lengths of the columns of the grid, given the areas of the
+// children, in the available space.
+func (c *Component) gridColumnLengths(children []*Component, areas []gridArea, numColumns, available int) []int {
+	items := make([]trackItem, len(children))
+	for i, child := range children {
+		before, after := child.margin.span(false)
+		items[i] = trackItem{
+			start:  areas[i].column,
+			span:   areas[i].columns,
+			length: before + child.intrinsicLength(false, 0) + after,
+		}
+	}
+	return trackLengths(c.gridColumns, numColumns, c.gap, available, items)
+}
+
+// Returns the lengths of the rows of the grid, given the areas of the children
+// and the lengths of the columns, in the available space.
+func (c *Component) gridRowLengths(children []*Component, areas []gridArea, columnLengths []int, numRows, available int) []int {
+	items := make([]trackItem, len(children))
+	for i, child := range children {
+		width := c.gap * (areas[i].columns - 1)
+		for _, length := range columnLengths[areas[i].column : areas[i].column+areas[i].columns] {
+			width += length
+		}
+		before, after := child.margin.span(false)
+		top, bottom := child.margin.span(true)
+		items[i] = trackItem{
+			start:  areas[i].row,
+			span:   areas[i].rows,
+			length: top + child.intrinsicLength(true, max(0, width-before-after)) + bottom,
+		}
+	}
+	return trackLengths(c.gridRows, numRows, c.crossGap, available, items)
+}
+
+// Returns the length of the grid along an axis that fits its tracks and
+// children, given its size along the other axis, not including padding.
+func (c *Component) gridIntrinsicLength(children []*Component, isVertical bool, crossSize int) int {
+	areas, numRows, numColumns := c.gridAreas(children)
+	available := 0
+	if isVertical {
+		available = crossSize
+	}
+	lengths := c.gridColumnLengths(children, areas, numColumns, available)
+	gap := c.gap
+	if isVertical {
+		lengths = c.gridRowLengths(children, areas, lengths, numRows, 0)
+		gap = c.crossGap
+	}
+
+	length := gap * max(0, len(lengths)-1)
+	for _, l := range lengths {
+		length += l
+	}
+	return length
+}
+
+// Compute the boxes of the given visible children according to the grid
+// layout of this Component. Each child fills its area inside its margins.
+func (c *Component) gridChildBoxes(children []*Component) []Box {
+	inner := c.contentBox()
+	areas, numRows, numColumns := c.gridAreas(children)
+	columnLengths := c.gridColumnLengths(children, areas, numColumns, inner.Width())
+	rowLengths := c.gridRowLengths(children, areas, columnLengths, numRows, inner.Height())
+
+	// The start of each track
+	columnStarts := make([]int, numColumns)
+	for i, pos := 0, inner.left; i < numColumns; i++ {
+		columnStarts[i] = pos
+		pos += columnLengths[i] + c.gap
+	}
+	rowStarts := make([]int, numRows)
+	for i, pos := 0, inner.top; i < numRows; i++ {
+		rowStarts[i] = pos
+		pos += rowLengths[i] + c.crossGap
+	}
+
+	boxes := make([]Box, len(children))
+	for i, child := range children {
+		area := areas[i]
+		lastRow := area.row + area.rows - 1
+		lastColumn := area.column + area.columns - 1
+		box := Box{
+			top:    rowStarts[area.row] + child.margin.Top,
+			left:   columnStarts[area.column] + child.margin.Left,
+			bottom: rowStarts[lastRow] + rowLengths[lastRow] - child.margin.Bottom,
+			right:  columnStarts[lastColumn] + columnLengths[lastColumn] - child.margin.Right,
+		}
+		box.bottom = max(box.top, box.bottom)
+		box.right = max(box.left, box.right)
+		boxes[i] = box
+	}
+	return boxes
+}
@@ -0,0 +1,403 @@
+package flextui
+
+import (
+	"math"
+	"sort"
+)
+
+// Returns the child Components that are not hidden, which are the only ones
+// that take part in the layout.
+func (c *Component) visibleChildren() []*Component {
+	visible := make([]*Component, 0, len(c.children))
+	for _, child := range c.children {
+		if !child.hidden {
+			visible = append(visible, child)
+		}
+	}
+	return visible
+}
+
+// Returns whether this Component's length is determined by its grow property.
+func (c *Component) isFlex() bool {
+	return c.length == 0 && !c.isRelative && !c.autoLength
+}
+
+// Returns the area inside this Component's padding, where its content and
+// children are laid out.
+func (c *Component) contentBox() Box {
+	return c.box.inset(&c.padding)
+}
+
+// Returns the length of this Component along an axis that fits its content,
+// or its children if it has any, given its size along the other axis.
+func (c *Component) intrinsicLength(isVertical bool, crossSize int) int {
+	before, after := c.padding.span(isVertical)
+	crossBefore, crossAfter := c.padding.span(!isVertical)
+	crossSize = max(0, crossSize-crossBefore-crossAfter)
+
+	children := c.visibleChildren()
+	length := 0
+	if c.isGrid() {
+		length = c.gridIntrinsicLength(children, isVertical, crossSize)
+	} else if len(children) == 0 {
+		length = c.content.measure(isVertical, crossSize)
+	} else if isVertical == c.isVertical {
+		// Children are laid out one after another along this axis. Relative
+		// lengths can't be resolved, since this is the length that they are
+		// relative to, so only their cells are counted.
+		length = c.gap * (len(children) - 1)
+		for _, child := range children {
+			childBefore, childAfter := child.margin.span(isVertical)
+			length += childBefore + int(child.baseLength(isVertical, 0, child.stretchedCrossLength(isVertical, crossSize))) + childAfter
+		}
+	} else {
+		// Children are laid out next to each other across this axis
+		lines := [][]*Component{children}
+		if c.wrap {
+			lines = c.wrapLines(children, crossSize, 0)
+		}
+		for i, crossLength := range c.lineCrossLengths(lines, crossSize, 0) {
+			if i > 0 {
+				length += c.crossGap
+			}
+			length += crossLength
+		}
+	}
+	return before + length + after
+}
+
+// Returns the length of this Component along its parent's main axis before
+// flex children grow: its fixed length, its relative length resolved against
+// the length of the parent's content box, the length that fits its content if
+// it has an auto length, or 0. The length is limited by its min/max length.
+func (c *Component) baseLength(isVertical bool, parentLength, crossSize int) float64 {
+	length := float64(c.length)
+	if c.isRelative {
+		length = c.relativeLength.resolve(parentLength)
+	} else if c.autoLength && length == 0 {
+		length = float64(c.intrinsicLength(isVertical, crossSize))
+	}
+	return c.clampLength(length)
+}
+
+// Returns the size of this Component across its parent's main axis when it is
+// not stretched: its cross length, or the size that fits its content given its
+// length along the main axis. The size is limited by its min/max cross length.
+func (c *Component) baseCrossLength(isVertical bool, length int) int {
+	crossLength := c.crossLength
+	if crossLength == 0 {
+		crossLength = c.intrinsicLength(!isVertical, length)
+	}
+	return c.clampCrossLength(crossLength)
+}
+
+// Returns the size of this Component across its parent's main axis when it is
+// stretched across the space available to its parent's children: its cross
+// length, or the available space without its margins. The size is limited by
+// its min/max cross length.
+func (c *Component) stretchedCrossLength(isVertical bool, available int) int {
+	if c.crossLength > 0 {
+		return c.clampCrossLength(c.crossLength)
+	}
+	before, after := c.margin.span(!isVertical)
+	return c.clampCrossLength(max(0, available-before-after))
+}
+
+// Compute the boxes of all child Components according to the flex layout of
+// this Component. The boxes are returned in the same order as the children,
+// and do not include the children's scrolling. Hidden children get an empty
+// box.
+func (c *Component) childBoxes() []Box {
+	children := c.visibleChildren()
+	visibleBoxes := c.visibleChildBoxes(children)
+
+	boxes := make([]Box, len(c.children))
+	i := 0
+	for j, child := range c.children {
+		if !child.hidden {
+			boxes[j] = visibleBoxes[i]
+			i++
+		}
+	}
+	return boxes
+}
+
+// Compute the boxes of the given visible children, in the same order.
+func (c *Component) visibleChildBoxes(children []*Component) []Box {
+	if c.isGrid() {
+		return c.gridChildBoxes(children)
+	}
+
+	boxes := make([]Box, len(children))
+	inner := c.contentBox()
+	mainStart, mainEnd := inner.span(c.isVertical)
+	crossStart, crossEnd := inner.span(!c.isVertical)
+
+	if !c.wrap {
+		c.layoutLine(boxes, children, 0, mainStart, mainEnd, crossStart, crossEnd)
+		return boxes
+	}
+
+	lines := c.wrapLines(children, mainEnd-mainStart, crossEnd-crossStart)
+	crossLengths := c.lineCrossLengths(lines, mainEnd-mainStart, crossEnd-crossStart)
+	first := 0 // The index of the first child on the current line
+	pos := crossStart
+	for i, line := range lines {
+		if i > 0 {
+			pos += c.crossGap
+		}
+		c.layoutLine(boxes, line, first, mainStart, mainEnd, pos, pos+crossLengths[i])
+		pos += crossLengths[i]
+		first += len(line)
+	}
+	return boxes
+}
+
+// Lay out a line of children, starting with the child at index first, in the
+// given area, and store their boxes in boxes.
+func (c *Component) layoutLine(boxes []Box, line []*Component, first, mainStart, mainEnd, crossStart, crossEnd int) {
+	lengths := c.flexLengths(line, mainEnd-mainStart, crossEnd-crossStart)
+
+	pos := mainStart
+	for i, child := range line {
+		before, after := child.margin.span(c.isVertical)
+		crossBefore, crossAfter := child.margin.span(!c.isVertical)
+		box := &boxes[first+i]
+
+		if i > 0 {
+			pos += c.gap
+		}
+		pos += before
+		box.setSpan(c.isVertical, pos, pos+lengths[i])
+		pos += lengths[i] + after
+
+		start, end := c.alignCross(child, lengths[i], crossStart+crossBefore, crossEnd-crossAfter)
+		box.setSpan(!c.isVertical, start, end)
+	}
+
+	// Distribute the space that is left over according to c.justify
+	if leftover := mainEnd - pos; leftover > 0 {
+		for i := range line {
+			offset := c.justifyOffset(i, len(line), leftover)
+			start, end := boxes[first+i].span(c.isVertical)
+			boxes[first+i].setSpan(c.isVertical, start+offset, end+offset)
+		}
+	}
+}
+
+// Split the given children into lines that each fit in the available space, based
+// on the children's base lengths, their margins and the gap. Every line
+// contains at least one child.
+func (c *Component) wrapLines(children []*Component, available, crossAvailable int) [][]*Component {
+	var lines [][]*Component
+	start := 0
+	pos := 0
+	for i, child := range children {
+		before, after := child.margin.span(c.isVertical)
+		length := before + int(child.baseLength(c.isVertical, available, child.stretchedCrossLength(c.isVertical, crossAvailable))) + after
+		if i > start {
+			if pos+c.gap+length > available {
+				lines = append(lines, children[start:i])
+				start = i
+				pos = 0
+			} else {
+				pos += c.gap
+			}
+		}
+		pos += length
+	}
+	if start < len(children) {
+		lines = append(lines, children[start:])
+	}
+	return lines
+}
+
+// Returns the size of each line along the cross axis, given the space
+// available along and across the main axis. A line is as large as its largest
+// child's base cross length, where flex children are assumed to fill the
+// line. If there is space left over, it is split evenly between the lines
+// that are empty.
+func (c *Component) lineCrossLengths(lines [][]*Component, available, crossAvailable int) []int {
+	crossLengths := make([]int, len(lines))
+	free := crossAvailable - c.crossGap*max(0, len(lines)-1)
+	var flexLines []int
+	for i, line := range lines {
+		for _, child := range line {
+			before, after := child.margin.span(!c.isVertical)
+			length := int(child.baseLength(c.isVertical, available, 0))
+			if child.isFlex() {
+				length = available
+			}
+			if crossLength := child.baseCrossLength(c.isVertical, length); crossLength > 0 {
+				crossLengths[i] = max(crossLengths[i], before+crossLength+after)
+			}
+		}
+		if crossLengths[i] == 0 {
+			flexLines = append(flexLines, i)
+		}
+		free -= crossLengths[i]
+	}
+
+	if free > 0 && len(flexLines) > 0 {
+		for n, i := range flexLines {
+			crossLengths[i] = free / len(flexLines)
+			if n < free%len(flexLines) {
+				crossLengths[i]++
+			}
+		}
+	}
+	return crossLengths
+}
+
+// Returns the lengths of the given children along the main axis when they are
+// laid out in the available space, with crossAvailable space across the main
+// axis.
+//
+// Fixed and auto length children keep their base length, and the remaining
+// space is split between flex children according to their grow. Like CSS
+// flex, when a flex child's share violates its min/max length, it is clamped
+// and the space it gives up or takes is redistributed among the remaining flex
+// children.
+//
+// Shares and relative lengths are rounded down, and the cells left over from
+// rounding are given one at a time to the growing and relative children with
+// the largest remainders, so that they always fill their space exactly.
+func (c *Component) flexLengths(children []*Component, available, crossAvailable int) []int {
+	lengths := make([]int, len(children))
+	targets := make([]float64, len(children))
+	growing := make([]bool, len(children))
+
+	// The space left for flex children after fixed lengths, gaps and margins
+	free := float64(available - c.gap*max(0, len(children)-1))
+	growSum := 0.0
+	for i, child := range children {
+		before, after := child.margin.span(c.isVertical)
+		free -= float64(before + after)
+		if child.isFlex() && child.grow > 0 {
+			growing[i] = true
+			growSum += child.grow
+		} else {
+			targets[i] = child.baseLength(c.isVertical, available, child.stretchedCrossLength(c.isVertical, crossAvailable))
+			free -= targets[i]
+		}
+	}
+
+	shares := make([]float64, len(children))
+	for growSum > 0 {
+		violation := 0.0
+		for i, child := range children {
+			if growing[i] {
+				shares[i] = max(0, free) * child.grow / growSum
+				targets[i] = child.clampLength(shares[i])
+				violation += targets[i] - shares[i]
+			}
+		}
+		if violation == 0 {
+			break
+		}
+
+		// Freeze the children that violate their min lengths if the total
+		// violation is positive, or their max lengths if it is negative
+		for i, child := range children {
+			if growing[i] && ((violation > 0 && targets[i] > shares[i]) || (violation < 0 && targets[i] < shares[i])) {
+				growing[i] = false
+				growSum -= child.grow
+				free -= targets[i]
+			}
+		}
+	}
+
+	// Growing children and children with relative lengths are rounded
+	// together, so that they fill the space they share exactly
+	var indices []int
+	total := 0.0
+	for i, child := range children {
+		if growing[i] || child.isRelative {
+			indices = append(indices, i)
+			total += targets[i]
+		} else {
+			lengths[i] = int(targets[i])
+		}
+	}
+	roundLengths(lengths, targets, indices, int(math.Round(total)))
+
+	return lengths
+}
+
+// Round the targets at the given indices down into lengths, and give the cells
+// left over from total one at a time to the lengths with the largest
+// remainders, breaking ties by order, so that they add up to total exactly.
+func roundLengths(lengths []int, targets []float64, indices []int, total int) {
+	for _, i := range indices {
+		lengths[i] = int(targets[i])
+		total -= lengths[i]
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		i, j := indices[a], indices[b]
+		return targets[i]-float64(lengths[i]) > targets[j]-float64(lengths[j])
+	})
+	for _, i := range indices[:max(0, min(total, len(indices)))] {
+		lengths[i]++
+	}
+}
+
+// Limit a length along the main axis to this Component's min/max length.
+func (c *Component) clampLength(length float64) float64 {
+	if c.maxLength > 0 {
+		length = min(length, float64(c.maxLength))
+	}
+	return max(length, float64(c.minLength))
+}
+
+// Limit a length across the main axis to this Component's min/max cross
+// length.
+func (c *Component) clampCrossLength(crossLength int) int {
+	if c.maxCrossLength > 0 {
+		crossLength = min(crossLength, c.maxCrossLength)
+	}
+	return max(crossLength, c.minCrossLength)
+}
+
+// Returns how far the child at index i of n children should be moved along
+// the main axis to distribute the leftover space according to c.justify.
+func (c *Component) justifyOffset(i, n, leftover int) int {
+	switch c.justify {
+	case Justify_End:
+		return leftover
+	case Justify_Center:
+		return leftover / 2
+	case Justify_SpaceBetween:
+		if n > 1 {
+			return leftover * i / (n - 1)
+		}
+	case Justify_SpaceAround:
+		return leftover * (2*i + 1) / (2 * n)
+	case Justify_SpaceEvenly:
+		return leftover * (i + 1) / (n + 1)
+	}
+	return 0
+}
+
+// Returns the start and end of a child's box along the cross axis, given its
+// length along the main axis and the space available to it, according to
+// c.align. Stretched children fill the available space unless they have a
+// cross length, and other children are as large as their base cross length.
+func (c *Component) alignCross(child *Component, length, start, end int) (int, int) {
+	available := max(0, end-start)
+	size := available
+	if child.crossLength > 0 {
+		size = child.crossLength
+	}
+	size = child.clampCrossLength(size)
+	if c.align != Align_Stretch {
+		size = child.baseCrossLength(c.isVertical, length)
+	}
+
+	switch c.align {
+	case Align_Center:
+		start += (available - size) / 2
+	case Align_End:
+		start += available - size
+	}
+	return start, start + size
+}
@@ -0,0 +1,472 @@
+package flextui_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+// Returns the lengths of the children of c along the axis they are laid out
+// in.
+func childLengths(c *flextui.Component) []int {
+	var lengths []int
+	for _, child := range c.Children() {
+		if c.IsVertical() {
+			lengths = append(lengths, child.Box().Height())
+		} else {
+			lengths = append(lengths, child.Box().Width())
+		}
+	}
+	return lengths
+}
+
+// Updates the layout of root, renders it, and returns the lines of the
+// terminal.
+func renderLines(root *flextui.Component, vt *flextui.VirtualTerminal) []string {
+	root.UpdateLayout()
+	root.Render()
+	_, height, _ := vt.Size()
+	lines := make([]string, height)
+	for row := range lines {
+		lines[row] = vt.Line(row)
+	}
+	return lines
+}
+
+// Returns the lines of the terminal with the blank cells that have a
+// background color replaced by "#", to show the boxes of Components that have
+// one.
+func backgroundLines(vt *flextui.VirtualTerminal) []string {
+	width, height, _ := vt.Size()
+	lines := make([]string, height)
+	for row := range lines {
+		for col := range width {
+			cell := vt.Cell(row, col)
+			if cell.Content == " " && cell.Style.Bg != flextui.Color_Default {
+				lines[row] += "#"
+			} else {
+				lines[row] += cell.Content
+			}
+		}
+	}
+	return lines
+}
+
+// Reports an error if the lines are not the wanted lines.
+func assertLines(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Got lines\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		build func(root *flextui.Component)
+		want  []int
+	}{
+		{
+			name:  "equal grow",
+			width: 100,
+			build: func(root *flextui.Component) {
+				for range 3 {
+					root.AddChild(flextui.NewComponent())
+				}
+			},
+			want: []int{34, 33, 33},
+		},
+		{
+			name:  "proportional grow",
+			width: 90,
+			build: func(root *flextui.Component) {
+				a := flextui.NewComponent()
+				a.SetGrow(2)
+				root.AddChild(a)
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{60, 30},
+		},
+		{
+			name:  "fixed length with gap and padding",
+			width: 50,
+			build: func(root *flextui.Component) {
+				root.SetPadding(flextui.AllSides(1))
+				root.SetGap(2)
+				a := flextui.NewComponent()
+				a.SetLength(20)
+				root.AddChild(a)
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{20, 26},
+		},
+		{
+			name:  "max length clamps a flex child",
+			width: 100,
+			build: func(root *flextui.Component) {
+				a := flextui.NewComponent()
+				a.SetMaxLength(10)
+				root.AddChild(a)
+				root.AddChild(flextui.NewComponent())
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{10, 45, 45},
+		},
+		{
+			name:  "min and max lengths",
+			width: 100,
+			build: func(root *flextui.Component) {
+				a := flextui.NewComponent()
+				a.SetMaxLength(10)
+				root.AddChild(a)
+				b := flextui.NewComponent()
+				b.SetMinLength(50)
+				root.AddChild(b)
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{10, 50, 40},
+		},
+		{
+			name:  "margins",
+			width: 30,
+			build: func(root *flextui.Component) {
+				a := flextui.NewComponent()
+				a.SetMargin(flextui.Spacing{Left: 2, Right: 3})
+				root.AddChild(a)
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{13, 12},
+		},
+		{
+			name:  "zero percent",
+			width: 40,
+			build: func(root *flextui.Component) {
+				a := flextui.NewComponent()
+				a.SetLengthDimension(flextui.Percent(0))
+				root.AddChild(a)
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{0, 40},
+		},
+		{
+			name:  "percent minus cells",
+			width: 40,
+			build: func(root *flextui.Component) {
+				a := flextui.NewComponent()
+				a.SetLengthDimension(flextui.Percent(50).Minus(2))
+				root.AddChild(a)
+				root.AddChild(flextui.NewComponent())
+			},
+			want: []int{18, 22},
+		},
+		{
+			name:  "relative thirds",
+			width: 100,
+			build: func(root *flextui.Component) {
+				for range 3 {
+					child := flextui.NewComponent()
+					child.SetLengthDimension(flextui.Fraction(1, 3))
+					root.AddChild(child)
+				}
+			},
+			want: []int{34, 33, 33},
+		},
+		{
+			name:  "relative halves",
+			width: 101,
+			build: func(root *flextui.Component) {
+				for range 2 {
+					child := flextui.NewComponent()
+					child.SetLengthDimension(flextui.Percent(50))
+					root.AddChild(child)
+				}
+			},
+			want: []int{51, 50},
+		},
+		{
+			name:  "grid fr columns",
+			width: 100,
+			build: func(root *flextui.Component) {
+				root.SetGridColumns(flextui.TrackFr(1), flextui.TrackFr(1), flextui.TrackFr(1))
+				for i := range 3 {
+					child := flextui.NewComponent()
+					child.SetGridCell(0, i)
+					root.AddChild(child)
+				}
+			},
+			want: []int{34, 33, 33},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, _ := flextuitest.NewScreen(test.width, 10)
+			test.build(root)
+			root.UpdateLayout()
+
+			flextuitest.AssertLayout(t, root)
+			got := childLengths(root)
+			if len(got) != len(test.want) {
+				t.Fatalf("Got %d children, want %d", len(got), len(test.want))
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("Child lengths are %v, want %v", got, test.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Adds a random tree of Components to parent, and returns all of them. The
+// content functions record the content box that they were last called with in
+// contentBoxes.
+func randomTree(r *rand.Rand, parent *flextui.Component, depth int, contentBoxes map[*flextui.Component]string) []*flextui.Component {
+	c := flextui.NewComponent()
+	parent.AddChild(c)
+	all := []*flextui.Component{c}
+	if depth > 0 && r.Intn(3) > 0 {
+		for range 1 + r.Intn(4) {
+			all = append(all, randomTree(r, c, depth-1, contentBoxes)...)
+		}
+	} else if r.Intn(2) == 0 {
+		content := strings.Repeat("y", r.Intn(20))
+		c.SetContentFunc(func(box *flextui.Box) string {
+			contentBoxes[c] = box.ToString()
+			return content
+		})
+	} else {
+		c.SetContent(strings.Repeat("x", r.Intn(20)))
+	}
+	return all
+}
+
+// Changes a random property of c.
+func mutate(r *rand.Rand, c *flextui.Component, vt *flextui.VirtualTerminal) {
+	switch r.Intn(11) {
+	case 0:
+		c.SetLength(r.Intn(6))
+	case 1:
+		c.SetGrow(float64(r.Intn(3)))
+	case 2:
+		c.SetAutoLength(r.Intn(2) == 0)
+	case 3:
+		c.SetPadding(flextui.AllSides(r.Intn(2)))
+	case 4:
+		c.SetIsVertical(r.Intn(2) == 0)
+	case 5:
+		c.Scroll.Top = r.Intn(3)
+		c.Scroll.Left = r.Intn(3)
+	case 6:
+		c.SetHidden(r.Intn(3) == 0)
+	case 7:
+		c.SetWrap(r.Intn(2) == 0)
+	case 8:
+		c.SetGap(r.Intn(2))
+	case 9:
+		vt.SetSize(20+r.Intn(40), 5+r.Intn(20))
+	case 10:
+		if children := c.Children(); len(children) > 1 {
+			c.MoveChild(children[0], r.Intn(len(children)))
+		}
+	}
+}
+
+// Returns the Boxes, content and content function Boxes of all of the
+// Components.
+func snapshot(all []*flextui.Component, contentBoxes map[*flextui.Component]string) []string {
+	var boxes []string
+	for _, c := range all {
+		content := ""
+		if c.Content() != nil {
+			content = *c.Content()
+		}
+		boxes = append(boxes, c.Box().ToString()+" "+content+" "+contentBoxes[c])
+	}
+	return boxes
+}
+
+func TestIncrementalLayout(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for tree := range 100 {
+		root, vt := flextuitest.NewScreen(50, 20)
+		contentBoxes := map[*flextui.Component]string{}
+		all := append([]*flextui.Component{root}, randomTree(r, root, 4, contentBoxes)...)
+		root.UpdateLayout()
+
+		for step := range 20 {
+			for range 1 + r.Intn(3) {
+				mutate(r, all[r.Intn(len(all))], vt)
+			}
+			root.UpdateLayout()
+			incremental := snapshot(all, contentBoxes)
+
+			for _, c := range all {
+				c.Invalidate()
+			}
+			root.UpdateLayout()
+			full := snapshot(all, contentBoxes)
+
+			for i := range all {
+				if incremental[i] != full[i] {
+					t.Fatalf("Tree %d, step %d: component %d is %q after an incremental layout, want %q", tree, step, i, incremental[i], full[i])
+				}
+			}
+		}
+	}
+}
+
+func TestConcurrentSetContent(t *testing.T) {
+	root, _ := flextuitest.NewScreen(40, 10)
+	var children []*flextui.Component
+	for range 4 {
+		child := flextui.NewComponent()
+		root.AddChild(child)
+		children = append(children, child)
+	}
+	root.UpdateLayout()
+
+	var wg sync.WaitGroup
+	for i, child := range children {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				child.SetContent(fmt.Sprint(i, j))
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			root.UpdateLayout()
+		}
+	}()
+	wg.Wait()
+
+	// The last changes must not be lost, even if they were made during a
+	// layout
+	root.UpdateLayout()
+	for i, child := range children {
+		if got, want := *child.Content(), fmt.Sprint(i, 99); got != want {
+			t.Errorf("Child %d has content %q, want %q", i, got, want)
+		}
+	}
+	flextuitest.AssertLayout(t, root)
+}
+
+func TestJustify(t *testing.T) {
+	tests := []struct {
+		justify int
+		want    string
+	}{
+		{flextui.Justify_Start, "AABB      "},
+		{flextui.Justify_End, "      AABB"},
+		{flextui.Justify_Center, "   AABB   "},
+		{flextui.Justify_SpaceBetween, "AA      BB"},
+		{flextui.Justify_SpaceAround, " AA   BB  "},
+		{flextui.Justify_SpaceEvenly, "  AA  BB  "},
+	}
+	for _, test := range tests {
+		root, vt := flextuitest.NewScreen(10, 1)
+		root.SetJustify(test.justify)
+		for _, content := range []string{"AA", "BB"} {
+			child := flextui.NewComponent()
+			child.SetLength(2)
+			child.SetContent(content)
+			root.AddChild(child)
+		}
+		assertLines(t, renderLines(root, vt), test.want)
+		flextuitest.AssertLayout(t, root)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		align int
+		want  []string
+	}{
+		{flextui.Align_Stretch, []string{"A##", "###", "###"}},
+		{flextui.Align_Start, []string{"A##", "   ", "   "}},
+		{flextui.Align_Center, []string{"   ", "A##", "   "}},
+		{flextui.Align_End, []string{"   ", "   ", "A##"}},
+	}
+	for _, test := range tests {
+		root, vt := flextuitest.NewScreen(3, 3)
+		root.SetAlign(test.align)
+		child := flextui.NewComponent()
+		child.SetContent("A")
+		child.SetStyle(flextui.Style{Bg: flextui.Color_Blue})
+		root.AddChild(child)
+		renderLines(root, vt)
+		assertLines(t, backgroundLines(vt), test.want...)
+		flextuitest.AssertLayout(t, root)
+	}
+}
+
+func TestConcurrentLengthSetters(t *testing.T) {
+	root, _ := flextuitest.NewScreen(40, 10)
+	child := flextui.NewComponent()
+	root.AddChild(child)
+	root.AddChild(flextui.NewComponent())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 1000 {
+			child.SetHidden(i%2 == 0)
+			child.SetAutoLength(i%3 == 0)
+			child.SetLengthDimension(flextui.Percent(float64(i % 100)))
+			child.SetLength(i % 5)
+			child.SetGrow(float64(i % 3))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			root.UpdateLayout()
+			flextuitest.AssertLayout(t, root)
+			return
+		default:
+			root.UpdateLayout()
+		}
+	}
+}
+
+func TestConcurrentLayoutAndRender(t *testing.T) {
+	root, vt := flextuitest.NewScreen(20, 10)
+	root.SetIsVertical(true)
+	for range 3 {
+		child := flextui.NewComponent()
+		child.SetAutoLength(true)
+		child.SetContent("some text that wraps onto several rows")
+		root.AddChild(child)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			vt.SetSize(20+i%5, 10)
+			root.UpdateLayout()
+		}
+	}()
+	// Components are rendered on their own, like when only part of the
+	// screen changes
+	go func() {
+		defer wg.Done()
+		for range 1000 {
+			root.Children()[1].Render()
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,134 @@
+package flextui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Span is a piece of text with its own Style. See [Component.SetSpans].
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Parses a color from its name, like "red" or "brightblue", an index in the
+// 256-color palette, like "208", or a hex code, like "#ff8700".
+func ParseColor(s string) (Color, error) {
+	if s == "default" {
+		return Color_Default, nil
+	}
+	for i, name := range colorNames {
+		if s == name {
+			return Color_Black + Color(i), nil
+		}
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok && len(hex) == 6 {
+		if rgb, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return ColorRGB(uint8(rgb>>16), uint8(rgb>>8), uint8(rgb)), nil
+		}
+	}
+	if index, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return Color256(uint8(index)), nil
+	}
+	return Color_Default, fmt.Errorf("invalid color %q", s)
+}
+
+// Parses a Style from a list of attributes separated by spaces, in the same
+// format as [Style.String]: "bold", "dim", "italic", "underline", "reverse",
+// "strikethrough", "fg=<color>" and "bg=<color>", where colors are parsed by
+// ParseColor(). A color name or hex code on its own sets the foreground color,
+// but an index in the 256-color palette needs "fg=". Example:
+// "bold red bg=#303030".
+func ParseStyle(s string) (Style, error) {
+	var style Style
+	for _, field := range strings.Fields(s) {
+		switch field {
+		case "bold":
+			style.Bold = true
+		case "dim":
+			style.Dim = true
+		case "italic":
+			style.Italic = true
+		case "underline":
+			style.Underline = true
+		case "reverse":
+			style.Reverse = true
+		case "strikethrough":
+			style.Strikethrough = true
+		default:
+			key, value, hasKey := strings.Cut(field, "=")
+			if !hasKey {
+				key, value = "fg", field
+			}
+			color, err := ParseColor(value)
+			// A number on its own is more likely to be text than a color
+			_, numberErr := strconv.Atoi(field)
+			if (key != "fg" && key != "bg") || (!hasKey && (err != nil || numberErr == nil)) {
+				return Style{}, fmt.Errorf("invalid style %q: unknown attribute %q", s, field)
+			}
+			if err != nil {
+				return Style{}, fmt.Errorf("invalid style %q: %w", s, err)
+			}
+			if key == "bg" {
+				style.Bg = color
+			} else {
+				style.Fg = color
+			}
+		}
+	}
+	return style, nil
+}
+
+// Parses text with inline styles into Spans. A style in square brackets, in
+// the format of ParseStyle(), applies to the text after it until a matching
+// "[/]". Styles can be nested, and "[[" is a literal "[". Example:
+// "[bold red]error:[/] file [underline]main.go[/] not found".
+func ParseMarkup(markup string) ([]Span, error) {
+	var spans []Span
+	stack := []Style{{}}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, Span{Text: text.String(), Style: stack[len(stack)-1]})
+			text.Reset()
+		}
+	}
+
+	rest := markup
+	for {
+		i := strings.IndexByte(rest, '[')
+		if i < 0 {
+			text.WriteString(rest)
+			break
+		}
+		text.WriteString(rest[:i])
+		rest = rest[i+1:]
+		if strings.HasPrefix(rest, "[") {
+			text.WriteByte('[')
+			rest = rest[1:]
+			continue
+		}
+
+		tag, after, ok := strings.Cut(rest, "]")
+		if !ok {
+			return nil, fmt.Errorf("invalid markup %q: unclosed tag %q", markup, "["+rest)
+		}
+		rest = after
+		flush()
+		if tag == "/" {
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("invalid markup %q: unexpected [/]", markup)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		style, err := ParseStyle(tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid markup %q: %w", markup, err)
+		}
+		stack = append(stack, stack[len(stack)-1].merge(style))
+	}
+	flush()
+	return spans, nil
+}
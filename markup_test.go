@@ -0,0 +1,57 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		s    string
+		want flextui.Style
+	}{
+		{"bold red", flextui.Style{Fg: flextui.Color_Red, Bold: true}},
+		{"#ff8700", flextui.Style{Fg: flextui.ColorRGB(0xff, 0x87, 0x00)}},
+		{"fg=208 bg=blue", flextui.Style{Fg: flextui.Color256(208), Bg: flextui.Color_Blue}},
+	}
+	for _, test := range tests {
+		got, err := flextui.ParseStyle(test.s)
+		if err != nil {
+			t.Errorf("ParseStyle(%q) returned an error: %v", test.s, err)
+		} else if got != test.want {
+			t.Errorf("ParseStyle(%q) = %v, want %v", test.s, got, test.want)
+		}
+	}
+
+	for _, s := range []string{"1", "208", "blink", "fg=purple", "ul=red"} {
+		if _, err := flextui.ParseStyle(s); err == nil {
+			t.Errorf("ParseStyle(%q) did not return an error", s)
+		}
+	}
+}
+
+func TestParseMarkup(t *testing.T) {
+	spans, err := flextui.ParseMarkup("[bold red]error:[/] [[x] [underline fg=208]a[/]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []flextui.Span{
+		{Text: "error:", Style: flextui.Style{Fg: flextui.Color_Red, Bold: true}},
+		{Text: " [x] "},
+		{Text: "a", Style: flextui.Style{Fg: flextui.Color256(208), Underline: true}},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("Got spans %v, want %v", spans, want)
+	}
+	for i := range spans {
+		if spans[i] != want[i] {
+			t.Errorf("Span %d is %v, want %v", i, spans[i], want[i])
+		}
+	}
+
+	// Numbers in brackets are not mistaken for colors
+	if _, err := flextui.ParseMarkup("[1] Dark Theme"); err == nil {
+		t.Error("ParseMarkup(\"[1] Dark Theme\") did not return an error")
+	}
+}
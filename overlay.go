@@ -0,0 +1,190 @@
+package flextui
+
+import "sort"
+
+// Where an overlay is positioned within its parent. See
+// [Component.SetAnchor].
+const (
+	Anchor_TopLeft = iota
+	Anchor_Top
+	Anchor_TopRight
+	Anchor_Left
+	Anchor_Center
+	Anchor_Right
+	Anchor_BottomLeft
+	Anchor_Bottom
+	Anchor_BottomRight
+)
+
+// Attaches an overlay Component to this Component. Overlays are not part of
+// the flex layout: they are positioned relative to this Component's Box
+// according to their anchor, offset and size, and they are drawn on top of
+// this Component and all of its children, in order of their z-index. Unlike
+// children, overlays can extend outside of this Component's Box, and are only
+// clipped to the Terminal. Useful for modals, dropdowns, tooltips and toasts.
+// If overlay already has a parent, it is removed from it first.
+func (c *Component) AddOverlay(overlay *Component) {
+	overlay.detach()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	overlay.parent = c
+	overlay.isOverlay = true
+	c.overlays = append(c.overlays, overlay)
+	overlay.markDirty()
+}
+
+// Removes an overlay Component from this Component. The area that it covered
+// is restored on the next Render() of any Component that renders to the same
+// Terminal.
+func (c *Component) RemoveOverlay(overlay *Component) {
+	c.mu.Lock()
+	removed := false
+	for i, o := range c.overlays {
+		if o == overlay {
+			c.overlays = append(c.overlays[:i], c.overlays[i+1:]...)
+			overlay.parent = nil
+			overlay.isOverlay = false
+			removed = true
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	if removed {
+		overlay.mu.Lock()
+		box := overlay.box
+		overlay.mu.Unlock()
+		c.rootDisplay().expose(box)
+	}
+}
+
+func (c *Component) Overlays() []*Component {
+	return c.overlays
+}
+
+func (c *Component) Anchor() int {
+	return c.anchor
+}
+
+func (c *Component) ZIndex() int {
+	return c.zIndex
+}
+
+// Set where this overlay is positioned within its parent, using one of the
+// flextui.Anchor_* constants. The default is Anchor_TopLeft.
+func (c *Component) SetAnchor(anchor int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.anchor = anchor
+	c.markDirty()
+}
+
+// Set how far this overlay is moved down and to the right from its anchored
+// position. Negative offsets move it up and to the left.
+func (c *Component) SetOffset(top, left int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.offsetTop = top
+	c.offsetLeft = left
+	c.markDirty()
+}
+
+// Set the width and height of this overlay. A width or height of 0 fits the
+// overlay's content or children.
+func (c *Component) SetSize(width, height int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.overlayWidth = width
+	c.overlayHeight = height
+	c.markDirty()
+}
+
+// Set the order in which this overlay is drawn relative to the other overlays
+// of its parent. Overlays with a higher z-index are drawn on top, and
+// overlays with the same z-index are drawn in the order they were added.
+func (c *Component) SetZIndex(zIndex int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.zIndex = zIndex
+}
+
+// Returns the overlays of this Component in the order they are drawn.
+func (c *Component) sortedOverlays() []*Component {
+	overlays := append([]*Component(nil), c.overlays...)
+	sort.SliceStable(overlays, func(i, j int) bool {
+		return overlays[i].zIndex < overlays[j].zIndex
+	})
+	return overlays
+}
+
+// Compute the box of one of this Component's overlays, not including its
+// scrolling.
+func (c *Component) overlayBox(overlay *Component) Box {
+	width := overlay.overlayWidth
+	if width == 0 {
+		width = overlay.intrinsicLength(false, c.box.Height())
+	}
+	height := overlay.overlayHeight
+	if height == 0 {
+		height = overlay.intrinsicLength(true, width)
+	}
+
+	top := c.box.top
+	left := c.box.left
+	switch overlay.anchor {
+	case Anchor_Left, Anchor_Center, Anchor_Right:
+		top += (c.box.Height() - height) / 2
+	case Anchor_BottomLeft, Anchor_Bottom, Anchor_BottomRight:
+		top = c.box.bottom - height
+	}
+	switch overlay.anchor {
+	case Anchor_Top, Anchor_Center, Anchor_Bottom:
+		left += (c.box.Width() - width) / 2
+	case Anchor_TopRight, Anchor_Right, Anchor_BottomRight:
+		left = c.box.right - width
+	}
+
+	top += overlay.offsetTop
+	left += overlay.offsetLeft
+	return Box{top: top, left: left, bottom: top + height, right: left + width}
+}
+
+// Draw the overlays of this Component's ancestors that are on top of this
+// Component, so that drawing only this Component doesn't cover them.
+func (c *Component) drawCoveringOverlays(f *frame) {
+	child := c
+	for parent := c.parent; parent != nil; parent = parent.parent {
+		overlays := parent.sortedOverlays()
+		if child.isOverlay {
+			// Only overlays after this one are on top of it
+			for i, overlay := range overlays {
+				if overlay == child {
+					overlays = overlays[i+1:]
+					break
+				}
+			}
+		}
+		for _, overlay := range overlays {
+			overlay.draw(f, f.box())
+		}
+		child = parent
+	}
+}
+
+// Returns the area of the frame that this Component is allowed to draw in,
+// which is inside the boxes of all of its ancestors up to the nearest overlay.
+func (c *Component) bounds(f *frame) Box {
+	bounds := f.box()
+	for child := c; child.parent != nil && !child.isOverlay; child = child.parent {
+		child.parent.mu.Lock()
+		box := child.parent.box
+		child.parent.mu.Unlock()
+		bounds = bounds.intersect(&box)
+	}
+	return bounds
+}
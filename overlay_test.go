@@ -0,0 +1,106 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestOverlayAnchor(t *testing.T) {
+	tests := []struct {
+		anchor    int
+		top, left int
+	}{
+		{flextui.Anchor_TopLeft, 0, 0},
+		{flextui.Anchor_Top, 0, 4},
+		{flextui.Anchor_TopRight, 0, 8},
+		{flextui.Anchor_Left, 2, 0},
+		{flextui.Anchor_Center, 2, 4},
+		{flextui.Anchor_Right, 2, 8},
+		{flextui.Anchor_BottomLeft, 4, 0},
+		{flextui.Anchor_Bottom, 4, 4},
+		{flextui.Anchor_BottomRight, 4, 8},
+	}
+	for _, test := range tests {
+		root, vt := flextuitest.NewScreen(10, 5)
+		overlay := flextui.NewComponent()
+		overlay.SetContent("XX")
+		overlay.SetAnchor(test.anchor)
+		root.AddOverlay(overlay)
+		renderLines(root, vt)
+
+		box := overlay.Box()
+		if box.Top() != test.top || box.Left() != test.left || box.Width() != 2 || box.Height() != 1 {
+			t.Errorf("Overlay with anchor %d has box %s, want top %d and left %d with size 2x1", test.anchor, box.ToString(), test.top, test.left)
+		}
+		if got := vt.Cell(test.top, test.left).Content; got != "X" {
+			t.Errorf("Overlay with anchor %d draws %q at %d, %d, want \"X\"", test.anchor, got, test.top, test.left)
+		}
+	}
+}
+
+// Returns a screen with a one row button at the top, and a dropdown below the
+// button that is an overlay of it.
+func newDropdown() (root, button, dropdown *flextui.Component, vt *flextui.VirtualTerminal) {
+	root, vt = flextuitest.NewScreen(10, 5)
+	root.SetIsVertical(true)
+	button = flextui.NewComponent()
+	button.SetLength(1)
+	button.SetContent("Button")
+	root.AddChild(button)
+	root.AddChild(flextui.NewComponent())
+
+	dropdown = flextui.NewComponent()
+	dropdown.SetContent("one\ntwo\nthree")
+	dropdown.SetAnchor(flextui.Anchor_BottomLeft)
+	dropdown.SetOffset(3, 0)
+	button.AddOverlay(dropdown)
+	return root, button, dropdown, vt
+}
+
+func TestOverlayOutsideParent(t *testing.T) {
+	root, _, _, vt := newDropdown()
+	assertLines(t, renderLines(root, vt),
+		"Button    ",
+		"one       ",
+		"two       ",
+		"three     ",
+		"          ",
+	)
+}
+
+func TestOverlayZIndex(t *testing.T) {
+	root, vt := flextuitest.NewScreen(4, 1)
+	for _, overlay := range []struct {
+		content string
+		zIndex  int
+	}{{"AAAA", 1}, {"BBB", 0}, {"CC", 1}} {
+		c := flextui.NewComponent()
+		c.SetContent(overlay.content)
+		c.SetZIndex(overlay.zIndex)
+		root.AddOverlay(c)
+	}
+	// B has the lowest z-index, and C is on top of A because it was added
+	// later
+	assertLines(t, renderLines(root, vt), "CCAA")
+}
+
+func TestRemoveOverlay(t *testing.T) {
+	root, button, dropdown, vt := newDropdown()
+	renderLines(root, vt)
+
+	// Rendering only the button restores the area outside of it that the
+	// dropdown covered
+	button.RemoveOverlay(dropdown)
+	assertLines(t, renderLines(button, vt),
+		"Button    ",
+		"          ",
+		"          ",
+		"          ",
+		"          ",
+	)
+	if len(button.Overlays()) != 0 {
+		t.Errorf("Button has %d overlays after removing the dropdown, want 0", len(button.Overlays()))
+	}
+}
@@ -0,0 +1,151 @@
+package flextui
+
+import (
+	"os"
+	"strings"
+)
+
+// The colors that a terminal can display. Styles are converted to the closest
+// colors that the terminal supports when they are rendered. See
+// [DetectColorProfile].
+const (
+	ColorProfile_None      = iota // No colors, only attributes like bold and underline
+	ColorProfile_16               // The 16 basic colors
+	ColorProfile_256              // The 256-color palette
+	ColorProfile_TrueColor        // 24-bit colors
+)
+
+// Returns the colors that the terminal supports according to the environment:
+// none if NO_COLOR is set or TERM is unset or "dumb", 24-bit colors if
+// COLORTERM is "truecolor" or "24bit", 256 colors if TERM ends in "256color",
+// and 16 colors otherwise.
+func DetectColorProfile() int {
+	term := os.Getenv("TERM")
+	if os.Getenv("NO_COLOR") != "" || term == "" || term == "dumb" {
+		return ColorProfile_None
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return ColorProfile_TrueColor
+	}
+
+	switch {
+	case strings.HasSuffix(term, "-direct") || strings.HasSuffix(term, "truecolor"):
+		return ColorProfile_TrueColor
+	case strings.HasSuffix(term, "256color"):
+		return ColorProfile_256
+	}
+	return ColorProfile_16
+}
+
+// The colors of the 256-color palette that basic colors and the first 16
+// indexed colors are usually displayed with, as in xterm.
+var basicColorsRGB = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0xcd, 0x00, 0x00}, {0x00, 0xcd, 0x00}, {0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee}, {0xcd, 0x00, 0xcd}, {0x00, 0xcd, 0xcd}, {0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f}, {0xff, 0x00, 0x00}, {0x00, 0xff, 0x00}, {0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff}, {0xff, 0x00, 0xff}, {0x00, 0xff, 0xff}, {0xff, 0xff, 0xff},
+}
+
+// The levels of red, green and blue in the 6x6x6 color cube of the 256-color
+// palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// Returns the red, green and blue components that a color is usually
+// displayed with.
+func (c Color) approximateRGB() (r, g, b uint8) {
+	value := int(c & colorValueMask)
+	switch c & colorKindMask {
+	case colorKindBasic:
+		rgb := basicColorsRGB[value]
+		return rgb[0], rgb[1], rgb[2]
+	case colorKindIndexed:
+		switch {
+		case value < 16:
+			rgb := basicColorsRGB[value]
+			return rgb[0], rgb[1], rgb[2]
+		case value < 232:
+			value -= 16
+			return cubeLevels[value/36], cubeLevels[value/6%6], cubeLevels[value%6]
+		}
+		gray := uint8(8 + 10*(value-232))
+		return gray, gray, gray
+	}
+	return c.RGB()
+}
+
+// Returns the square of the distance between two colors.
+func colorDistance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+// Returns the closest color that can be displayed with the given color
+// profile.
+func (c Color) convert(profile int) Color {
+	kind := c & colorKindMask
+	switch {
+	case c == Color_Default || profile == ColorProfile_TrueColor:
+		return c
+	case profile == ColorProfile_None:
+		return Color_Default
+	case kind == colorKindBasic:
+		return c
+	case kind == colorKindIndexed && c&colorValueMask < 16:
+		return Color_Black + c&colorValueMask
+	}
+
+	r, g, b := c.approximateRGB()
+	if profile == ColorProfile_16 {
+		best, bestDistance := 0, -1
+		for i, rgb := range basicColorsRGB {
+			if d := colorDistance(r, g, b, rgb[0], rgb[1], rgb[2]); bestDistance < 0 || d < bestDistance {
+				best, bestDistance = i, d
+			}
+		}
+		return Color_Black + Color(best)
+	}
+
+	if kind == colorKindIndexed {
+		return c
+	}
+	// Choose the closer of the closest color in the color cube and the closest
+	// gray in the grayscale ramp
+	nearestLevel := func(v uint8) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if colorDistance(v, 0, 0, level, 0, 0) < colorDistance(v, 0, 0, cubeLevels[best], 0, 0) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cube := Color256(uint8(16 + 36*ri + 6*gi + bi))
+	grayIndex := min(23, max(0, (int(r)+int(g)+int(b))/3-3)/10)
+	gray := Color256(uint8(232 + grayIndex))
+
+	cr, cg, cb := cube.approximateRGB()
+	gr, gg, gb := gray.approximateRGB()
+	if colorDistance(r, g, b, gr, gg, gb) < colorDistance(r, g, b, cr, cg, cb) {
+		return gray
+	}
+	return cube
+}
+
+// Returns the style with its colors converted to ones that can be displayed
+// with the given color profile.
+func (s Style) convert(profile int) Style {
+	s.Fg = s.Fg.convert(profile)
+	s.Bg = s.Bg.convert(profile)
+	return s
+}
+
+// Returns the color profile of a Terminal. Terminals that don't report one
+// are assumed to support 24-bit colors.
+func terminalColorProfile(terminal Terminal) int {
+	if t, ok := terminal.(interface{ ColorProfile() int }); ok {
+		return t.ColorProfile()
+	}
+	return ColorProfile_TrueColor
+}
@@ -0,0 +1,68 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		noColor, term, colorTerm string
+		want                     int
+	}{
+		{"1", "xterm-256color", "truecolor", flextui.ColorProfile_None},
+		{"", "", "truecolor", flextui.ColorProfile_None},
+		{"", "dumb", "truecolor", flextui.ColorProfile_None},
+		{"", "xterm", "truecolor", flextui.ColorProfile_TrueColor},
+		{"", "xterm", "24bit", flextui.ColorProfile_TrueColor},
+		{"", "xterm-direct", "", flextui.ColorProfile_TrueColor},
+		{"", "xterm-256color", "", flextui.ColorProfile_256},
+		{"", "screen-256color", "yes", flextui.ColorProfile_256},
+		{"", "xterm", "", flextui.ColorProfile_16},
+		{"", "linux", "", flextui.ColorProfile_16},
+	}
+	for _, test := range tests {
+		t.Setenv("NO_COLOR", test.noColor)
+		t.Setenv("TERM", test.term)
+		t.Setenv("COLORTERM", test.colorTerm)
+		if got := flextui.DetectColorProfile(); got != test.want {
+			t.Errorf("DetectColorProfile() with NO_COLOR=%q TERM=%q COLORTERM=%q is %d, want %d", test.noColor, test.term, test.colorTerm, got, test.want)
+		}
+	}
+}
+
+func TestColorProfileConversion(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile int
+		color   flextui.Color
+		want    flextui.Color
+	}{
+		{"true color is kept", flextui.ColorProfile_TrueColor, flextui.ColorRGB(1, 2, 3), flextui.ColorRGB(1, 2, 3)},
+		{"rgb to color cube", flextui.ColorProfile_256, flextui.ColorRGB(255, 135, 0), flextui.Color256(208)},
+		{"rgb to grayscale ramp", flextui.ColorProfile_256, flextui.ColorRGB(128, 128, 128), flextui.Color256(244)},
+		{"indexed is kept", flextui.ColorProfile_256, flextui.Color256(100), flextui.Color256(100)},
+		{"basic is kept", flextui.ColorProfile_256, flextui.Color_Cyan, flextui.Color_Cyan},
+		{"rgb to nearest basic", flextui.ColorProfile_16, flextui.ColorRGB(250, 5, 5), flextui.Color_BrightRed},
+		{"cube to nearest basic", flextui.ColorProfile_16, flextui.Color256(208), flextui.Color_Yellow},
+		{"gray to nearest basic", flextui.ColorProfile_16, flextui.Color256(244), flextui.Color_BrightBlack},
+		{"first 16 indexed to basic", flextui.ColorProfile_16, flextui.Color256(9), flextui.Color_BrightRed},
+		{"no colors", flextui.ColorProfile_None, flextui.Color_Red, flextui.Color_Default},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, vt := flextuitest.NewScreen(1, 1)
+			vt.SetColorProfile(test.profile)
+			root.SetStyle(flextui.Style{Fg: test.color, Bg: test.color})
+			root.SetContent("x")
+			renderLines(root, vt)
+
+			style := vt.Cell(0, 0).Style
+			if style.Fg != test.want || style.Bg != test.want {
+				t.Errorf("Color %v is drawn as fg %v and bg %v, want %v", test.color, style.Fg, style.Bg, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package flextui
+
+// The amount of space on each side of a Component. See
+// [Component.SetPadding] and [Component.SetMargin].
+type Spacing struct {
+	Top    int
+	Left   int
+	Right  int
+	Bottom int
+}
+
+// Returns a Spacing with the same amount of space on all sides.
+func AllSides(n int) Spacing {
+	return Spacing{Top: n, Left: n, Right: n, Bottom: n}
+}
+
+// Returns the space before and after a Component along an axis.
+func (s *Spacing) span(isVertical bool) (int, int) {
+	if isVertical {
+		return s.Top, s.Bottom
+	}
+	return s.Left, s.Right
+}
@@ -0,0 +1,424 @@
+package flextui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Color is a terminal color. The zero value is the terminal's default color.
+// Use the Color_* constants for the 16 basic colors, or Color256() and
+// ColorRGB() for extended colors.
+type Color uint32
+
+const (
+	colorKindBasic   Color = 1 << 24
+	colorKindIndexed Color = 2 << 24
+	colorKindRGB     Color = 3 << 24
+
+	colorKindMask  Color = 0xff << 24
+	colorValueMask Color = 0xffffff
+)
+
+const Color_Default Color = 0
+
+const (
+	Color_Black Color = colorKindBasic | iota
+	Color_Red
+	Color_Green
+	Color_Yellow
+	Color_Blue
+	Color_Magenta
+	Color_Cyan
+	Color_White
+	Color_BrightBlack
+	Color_BrightRed
+	Color_BrightGreen
+	Color_BrightYellow
+	Color_BrightBlue
+	Color_BrightMagenta
+	Color_BrightCyan
+	Color_BrightWhite
+)
+
+var colorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"brightblack", "brightred", "brightgreen", "brightyellow", "brightblue", "brightmagenta", "brightcyan", "brightwhite",
+}
+
+// Returns a color from the 256-color palette.
+func Color256(index uint8) Color {
+	return colorKindIndexed | Color(index)
+}
+
+// Returns a 24-bit color.
+func ColorRGB(r, g, b uint8) Color {
+	return colorKindRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// Returns the red, green and blue components of a color created with
+// ColorRGB().
+func (c Color) RGB() (r, g, b uint8) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c)
+}
+
+func (c Color) String() string {
+	switch c & colorKindMask {
+	case colorKindBasic:
+		return colorNames[c&colorValueMask]
+	case colorKindIndexed:
+		return fmt.Sprintf("%d", c&colorValueMask)
+	case colorKindRGB:
+		r, g, b := c.RGB()
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	return "default"
+}
+
+// The visual attributes of a terminal cell.
+type Style struct {
+	Fg Color
+	Bg Color
+
+	Bold          bool
+	Dim           bool
+	Italic        bool
+	Underline     bool
+	Reverse       bool
+	Strikethrough bool
+}
+
+// Returns the style with the foreground and background colors that it
+// doesn't set taken from the style of its parent.
+func (s Style) inherit(parent Style) Style {
+	if s.Fg == Color_Default {
+		s.Fg = parent.Fg
+	}
+	if s.Bg == Color_Default {
+		s.Bg = parent.Bg
+	}
+	return s
+}
+
+// Returns the style that text in a span of style over is drawn with inside a
+// Component of style s. Colors that are set in over replace the colors of s,
+// and attributes are added to those of s.
+func (s Style) merge(over Style) Style {
+	if over.Fg != Color_Default {
+		s.Fg = over.Fg
+	}
+	if over.Bg != Color_Default {
+		s.Bg = over.Bg
+	}
+	s.Bold = s.Bold || over.Bold
+	s.Dim = s.Dim || over.Dim
+	s.Italic = s.Italic || over.Italic
+	s.Underline = s.Underline || over.Underline
+	s.Reverse = s.Reverse || over.Reverse
+	s.Strikethrough = s.Strikethrough || over.Strikethrough
+	return s
+}
+
+func (s Style) String() string {
+	var parts []string
+	if s.Fg != Color_Default {
+		parts = append(parts, "fg="+s.Fg.String())
+	}
+	if s.Bg != Color_Default {
+		parts = append(parts, "bg="+s.Bg.String())
+	}
+	for _, attr := range []struct {
+		on   bool
+		name string
+	}{
+		{s.Bold, "bold"},
+		{s.Dim, "dim"},
+		{s.Italic, "italic"},
+		{s.Underline, "underline"},
+		{s.Reverse, "reverse"},
+		{s.Strikethrough, "strikethrough"},
+	} {
+		if attr.on {
+			parts = append(parts, attr.name)
+		}
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Update the style according to the parameters of an SGR escape sequence
+// (ESC [ ... m).
+func (s *Style) applySGR(params []int) {
+	if len(params) == 0 {
+		*s = Style{}
+		return
+	}
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			*s = Style{}
+		case p == 1:
+			s.Bold = true
+		case p == 2:
+			s.Dim = true
+		case p == 3:
+			s.Italic = true
+		case p == 4:
+			s.Underline = true
+		case p == 7:
+			s.Reverse = true
+		case p == 9:
+			s.Strikethrough = true
+		case p == 22:
+			s.Bold = false
+			s.Dim = false
+		case p == 23:
+			s.Italic = false
+		case p == 24:
+			s.Underline = false
+		case p == 27:
+			s.Reverse = false
+		case p == 29:
+			s.Strikethrough = false
+		case p >= 30 && p <= 37:
+			s.Fg = Color_Black + Color(p-30)
+		case p == 38 || p == 48:
+			color, n := parseExtendedColor(params[i+1:])
+			i += n
+			if p == 38 {
+				s.Fg = color
+			} else {
+				s.Bg = color
+			}
+		case p == 39:
+			s.Fg = Color_Default
+		case p >= 40 && p <= 47:
+			s.Bg = Color_Black + Color(p-40)
+		case p == 49:
+			s.Bg = Color_Default
+		case p >= 90 && p <= 97:
+			s.Fg = Color_BrightBlack + Color(p-90)
+		case p >= 100 && p <= 107:
+			s.Bg = Color_BrightBlack + Color(p-100)
+		}
+	}
+}
+
+// Parse the parameters following a 38 or 48 SGR parameter. Returns the color
+// and the number of parameters consumed.
+func parseExtendedColor(params []int) (Color, int) {
+	if len(params) >= 2 && params[0] == 5 {
+		return Color256(uint8(params[1])), 2
+	}
+	if len(params) >= 4 && params[0] == 2 {
+		return ColorRGB(uint8(params[1]), uint8(params[2]), uint8(params[3])), 4
+	}
+	return Color_Default, len(params)
+}
+
+// Returns the SGR escape sequence that resets all attributes and then applies
+// the style.
+func sgr(s Style) string {
+	params := []string{"0"}
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Dim {
+		params = append(params, "2")
+	}
+	if s.Italic {
+		params = append(params, "3")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if s.Reverse {
+		params = append(params, "7")
+	}
+	if s.Strikethrough {
+		params = append(params, "9")
+	}
+	if s.Fg != Color_Default {
+		params = append(params, s.Fg.sgrParams(false))
+	}
+	if s.Bg != Color_Default {
+		params = append(params, s.Bg.sgrParams(true))
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// Returns the shortest SGR escape sequence that changes the terminal's
+// attributes from one style to another: either one that only changes the
+// attributes that differ, or one that resets all attributes first.
+func sgrChange(from, to Style) string {
+	if from == to {
+		return ""
+	}
+
+	var params []string
+	// Bold and dim can only be turned off together
+	if (from.Bold && !to.Bold) || (from.Dim && !to.Dim) {
+		params = append(params, "22")
+		from.Bold = false
+		from.Dim = false
+	}
+	for _, attr := range []struct {
+		from, to bool
+		on, off  string
+	}{
+		{from.Bold, to.Bold, "1", ""},
+		{from.Dim, to.Dim, "2", ""},
+		{from.Italic, to.Italic, "3", "23"},
+		{from.Underline, to.Underline, "4", "24"},
+		{from.Reverse, to.Reverse, "7", "27"},
+		{from.Strikethrough, to.Strikethrough, "9", "29"},
+	} {
+		if attr.to && !attr.from {
+			params = append(params, attr.on)
+		} else if attr.from && !attr.to {
+			params = append(params, attr.off)
+		}
+	}
+	if from.Fg != to.Fg {
+		params = append(params, to.Fg.sgrParams(false))
+	}
+	if from.Bg != to.Bg {
+		params = append(params, to.Bg.sgrParams(true))
+	}
+
+	change := "\033[" + strings.Join(params, ";") + "m"
+	if reset := sgr(to); len(reset) < len(change) {
+		return reset
+	}
+	return change
+}
+
+// Returns the SGR parameters that select this color as the foreground or
+// background color.
+func (c Color) sgrParams(isBg bool) string {
+	offset := 0
+	if isBg {
+		offset = 10
+	}
+	value := int(c & colorValueMask)
+	switch c & colorKindMask {
+	case colorKindBasic:
+		if value < 8 {
+			return fmt.Sprintf("%d", 30+offset+value)
+		}
+		return fmt.Sprintf("%d", 90+offset+value-8)
+	case colorKindIndexed:
+		return fmt.Sprintf("%d;5;%d", 38+offset, value)
+	case colorKindRGB:
+		r, g, b := c.RGB()
+		return fmt.Sprintf("%d;2;%d;%d;%d", 38+offset, r, g, b)
+	}
+	return fmt.Sprintf("%d", 39+offset)
+}
+
+// Apply all SGR escape sequences found in text to the style.
+func (s *Style) applyEscapes(text string) {
+	for {
+		start := strings.Index(text, "\033[")
+		if start == -1 {
+			return
+		}
+		text = text[start+2:]
+		end := strings.IndexFunc(text, func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+		if end == -1 {
+			return
+		}
+		if text[end] == 'm' {
+			s.applySGR(parseParams(text[:end]))
+		}
+		text = text[end+1:]
+	}
+}
+
+// Split text into its characters without any escape sequences, and the runs
+// of styles that its SGR sequences apply to them. Runs start at byte offsets
+// in the returned text.
+func parseEscapes(text string) (string, []styleRun) {
+	var builder strings.Builder
+	var styles []styleRun
+	var style Style
+	for {
+		start := strings.IndexByte(text, '\033')
+		if start == -1 {
+			builder.WriteString(text)
+			return builder.String(), styles
+		}
+		builder.WriteString(text[:start])
+		text = text[start+1:]
+
+		switch {
+		case strings.HasPrefix(text, "["):
+			// Control sequences end with a byte in the range 0x40-0x7e
+			text = text[1:]
+			end := strings.IndexFunc(text, func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+			if end == -1 {
+				return builder.String(), styles
+			}
+			if text[end] == 'm' {
+				style.applySGR(parseParams(text[:end]))
+				if n := len(styles); n > 0 && styles[n-1].start == builder.Len() {
+					styles[n-1].style = style
+				} else {
+					styles = append(styles, styleRun{start: builder.Len(), style: style})
+				}
+			}
+			text = text[end+1:]
+		case strings.HasPrefix(text, "]"):
+			// Operating system commands end with BEL or ESC \
+			end := strings.IndexAny(text, "\a\033")
+			if end == -1 {
+				return builder.String(), styles
+			}
+			if text[end] == '\a' {
+				text = text[end+1:]
+			} else if strings.HasPrefix(text[end+1:], "\\") {
+				text = text[end+2:]
+			} else {
+				text = text[end:]
+			}
+		default:
+			// Other escape sequences are any number of bytes in the range
+			// 0x20-0x2f followed by a byte in the range 0x30-0x7e
+			end := strings.IndexFunc(text, func(r rune) bool { return r < 0x20 || r > 0x2f })
+			if end == -1 {
+				return builder.String(), styles
+			}
+			if text[end] >= 0x30 && text[end] <= 0x7e {
+				end++
+			}
+			text = text[end:]
+		}
+	}
+}
+
+// Parse the semicolon separated parameters of a control sequence.
+func parseParams(paramString string) []int {
+	var params []int
+	if paramString != "" {
+		for _, field := range strings.FieldsFunc(paramString, func(r rune) bool { return r == ';' || r == ':' }) {
+			n, _ := strconv.Atoi(field)
+			params = append(params, n)
+		}
+	}
+	return params
+}
+
+// Returns the Style that a ColorFunc applies to text, by inspecting the escape
+// sequences that it adds before its argument. Useful for converting ColorFuncs
+// such as [github.com/fatih/color.Color.SprintFunc] to Styles.
+func StyleFromColorFunc(colorFunc func(a ...any) string) Style {
+	var s Style
+	if colorFunc != nil {
+		prefix, _, _ := strings.Cut(colorFunc("\x00"), "\x00")
+		s.applyEscapes(prefix)
+	}
+	return s
+}
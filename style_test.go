@@ -0,0 +1,62 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestStyleInheritance(t *testing.T) {
+	root, vt := flextuitest.NewScreen(4, 2)
+	root.SetIsVertical(true)
+	root.SetStyle(flextui.Style{Fg: flextui.Color_White, Bg: flextui.Color_Blue})
+	panel := flextui.NewComponent()
+	root.AddChild(panel)
+	label := flextui.NewComponent()
+	label.SetContent("ab")
+	label.SetStyle(flextui.Style{Fg: flextui.Color_Yellow, Bold: true})
+	panel.AddChild(label)
+	other := flextui.NewComponent()
+	other.SetContent("cd")
+	root.AddChild(other)
+
+	assertStyle := func(row, col int, want flextui.Style) {
+		t.Helper()
+		if got := vt.Cell(row, col).Style; got != want {
+			t.Errorf("Cell %d, %d has style %v, want %v", row, col, got, want)
+		}
+	}
+
+	// Children take the colors they don't set from their ancestors
+	renderLines(root, vt)
+	assertStyle(0, 0, flextui.Style{Fg: flextui.Color_Yellow, Bg: flextui.Color_Blue, Bold: true})
+	assertStyle(0, 3, flextui.Style{Fg: flextui.Color_Yellow, Bg: flextui.Color_Blue, Bold: true})
+	assertStyle(1, 0, flextui.Style{Fg: flextui.Color_White, Bg: flextui.Color_Blue})
+
+	// Changing the root's style reaches descendants that didn't change
+	root.SetStyle(flextui.Style{Bg: flextui.Color_Red})
+	renderLines(root, vt)
+	assertStyle(0, 0, flextui.Style{Fg: flextui.Color_Yellow, Bg: flextui.Color_Red, Bold: true})
+	assertStyle(1, 3, flextui.Style{Bg: flextui.Color_Red})
+
+	// A child's own background covers its parent's
+	panel.SetStyle(flextui.Style{Bg: flextui.Color_Green})
+	renderLines(root, vt)
+	assertStyle(0, 0, flextui.Style{Fg: flextui.Color_Yellow, Bg: flextui.Color_Green, Bold: true})
+	assertStyle(1, 0, flextui.Style{Bg: flextui.Color_Red})
+}
+
+func TestOverlayStyleInheritance(t *testing.T) {
+	root, vt := flextuitest.NewScreen(4, 2)
+	root.SetStyle(flextui.Style{Bg: flextui.Color_Blue})
+	overlay := flextui.NewComponent()
+	overlay.SetContent("x")
+	overlay.SetAnchor(flextui.Anchor_BottomRight)
+	root.AddOverlay(overlay)
+	renderLines(root, vt)
+
+	if got := vt.Cell(1, 3).Style.Bg; got != flextui.Color_Blue {
+		t.Errorf("Overlay has background %v, want blue", got)
+	}
+}
@@ -0,0 +1,121 @@
+package flextui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// A Terminal is an output backend that Components can be rendered to. Render()
+// writes ANSI escape sequences to it, and UpdateLayout() uses its Size() to
+// size the root Component that it is bound to. See
+// [Component.SetTerminal].
+//
+// Terminals that also have a ColorProfile() int method, returning one of the
+// ColorProfile_* constants, are only sent colors in that profile. Other
+// Terminals are sent colors as they are.
+type Terminal interface {
+	io.Writer
+
+	// Returns the width and height of the terminal in cells.
+	Size() (width, height int, err error)
+
+	HideCursor()
+	ShowCursor()
+
+	// Moves the cursor to the given 1-indexed row and column.
+	CursorTo(row, col int)
+
+	// Clears the entire terminal.
+	Clear()
+}
+
+// A Terminal that writes ANSI escape sequences to an io.Writer.
+type AnsiTerminal struct {
+	out io.Writer
+	fd  int // File descriptor used to query the size, or -1 for a fixed size
+
+	width  int
+	height int
+
+	colorProfile int
+
+	mu sync.Mutex
+}
+
+// Creates a Terminal that writes to the given file, usually [os.Stdout]. Its
+// size is queried from the file, so it must be a TTY. Its color profile is
+// detected with DetectColorProfile().
+func NewAnsiTerminal(f *os.File) *AnsiTerminal {
+	return &AnsiTerminal{out: f, fd: int(f.Fd()), colorProfile: DetectColorProfile()}
+}
+
+// Creates a Terminal that writes to any io.Writer, such as a file, a buffer or
+// a pty, and always reports the given size. Its color profile is detected
+// with DetectColorProfile().
+func NewFixedSizeTerminal(w io.Writer, width, height int) *AnsiTerminal {
+	return &AnsiTerminal{out: w, fd: -1, width: width, height: height, colorProfile: DetectColorProfile()}
+}
+
+// Returns the colors that the terminal supports, as one of the
+// ColorProfile_* constants.
+func (t *AnsiTerminal) ColorProfile() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.colorProfile
+}
+
+// Override the colors that the terminal supports, e.g. to respect a --color
+// command line flag.
+func (t *AnsiTerminal) SetColorProfile(colorProfile int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.colorProfile = colorProfile
+}
+
+// Change the size reported by a Terminal created with NewFixedSizeTerminal().
+func (t *AnsiTerminal) SetSize(width, height int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.width = width
+	t.height = height
+}
+
+func (t *AnsiTerminal) Size() (int, int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.fd < 0 {
+		return t.width, t.height, nil
+	}
+	return term.GetSize(t.fd)
+}
+
+func (t *AnsiTerminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.out.Write(p)
+}
+
+func (t *AnsiTerminal) HideCursor() {
+	io.WriteString(t, "\033[?25l")
+}
+
+func (t *AnsiTerminal) ShowCursor() {
+	io.WriteString(t, "\033[?25h")
+}
+
+func (t *AnsiTerminal) CursorTo(row, col int) {
+	fmt.Fprintf(t, "\033[%d;%dH", row, col)
+}
+
+func (t *AnsiTerminal) Clear() {
+	io.WriteString(t, "\033[H\033[2J")
+}
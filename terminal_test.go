@@ -0,0 +1,41 @@
+package flextui_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/computerdane/flextui"
+)
+
+// A Terminal whose size can't be read after it is closed.
+type closingTerminal struct {
+	*flextui.VirtualTerminal
+	closed bool
+}
+
+func (t *closingTerminal) Size() (int, int, error) {
+	if t.closed {
+		return 0, 0, errors.New("terminal closed")
+	}
+	return t.VirtualTerminal.Size()
+}
+
+func TestUpdateLayoutKeepsSizeOnError(t *testing.T) {
+	terminal := &closingTerminal{VirtualTerminal: flextui.NewVirtualTerminal(20, 5)}
+	root := flextui.NewComponent()
+	root.SetTerminal(terminal)
+	child := flextui.NewComponent()
+	root.AddChild(child)
+	root.UpdateLayout()
+
+	terminal.closed = true
+	child.SetPadding(flextui.AllSides(1))
+	root.UpdateLayout()
+
+	if got, want := root.Box().Width(), 20; got != want {
+		t.Errorf("Root width is %d, want %d", got, want)
+	}
+	if got, want := root.Box().Height(), 5; got != want {
+		t.Errorf("Root height is %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,435 @@
+package flextui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A single character cell of a terminal.
+type Cell struct {
+	Content string
+	Width   int // The number of columns the content spans, or 0 for the columns covered by a wide character
+	Style   Style
+}
+
+var blankCell = Cell{Content: BLANK_CHAR, Width: 1}
+
+// A headless, in-memory Terminal. It interprets the escape sequences written
+// by Render() (cursor positioning, SGR colors, cursor visibility and clearing)
+// into a grid of Cells that can be read back. Useful for testing layouts
+// without a real TTY.
+type VirtualTerminal struct {
+	width  int
+	height int
+	cells  []Cell
+
+	row           int
+	col           int
+	wrapPending   bool
+	cursorVisible bool
+	style         Style
+	colorProfile  int
+
+	// Bytes of an incomplete UTF-8 sequence or escape sequence from the previous
+	// Write()
+	pending []byte
+
+	mu sync.Mutex
+}
+
+func NewVirtualTerminal(width, height int) *VirtualTerminal {
+	t := &VirtualTerminal{cursorVisible: true, colorProfile: ColorProfile_TrueColor}
+	t.resize(width, height)
+	return t
+}
+
+func (t *VirtualTerminal) resize(width, height int) {
+	width = max(0, width)
+	height = max(0, height)
+	cells := make([]Cell, width*height)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			if row < t.height && col < t.width {
+				cells[row*width+col] = t.cells[row*t.width+col]
+			} else {
+				cells[row*width+col] = blankCell
+			}
+		}
+	}
+	t.width = width
+	t.height = height
+	t.cells = cells
+	t.row = min(t.row, max(0, height-1))
+	t.col = min(t.col, max(0, width-1))
+	t.wrapPending = false
+}
+
+// Returns the colors that the terminal supports. Defaults to
+// ColorProfile_TrueColor.
+func (t *VirtualTerminal) ColorProfile() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.colorProfile
+}
+
+// Set the colors that the terminal supports, to test how Styles are rendered
+// on terminals with fewer colors.
+func (t *VirtualTerminal) SetColorProfile(colorProfile int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.colorProfile = colorProfile
+}
+
+// Change the size of the terminal. Existing cells that fit in the new size
+// are kept.
+func (t *VirtualTerminal) SetSize(width, height int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resize(width, height)
+}
+
+func (t *VirtualTerminal) Size() (int, int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.width, t.height, nil
+}
+
+func (t *VirtualTerminal) HideCursor() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cursorVisible = false
+}
+
+func (t *VirtualTerminal) ShowCursor() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cursorVisible = true
+}
+
+func (t *VirtualTerminal) CursorTo(row, col int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.moveTo(row-1, col-1)
+}
+
+func (t *VirtualTerminal) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.moveTo(0, 0)
+	t.erase(0, len(t.cells))
+}
+
+// Returns the 0-indexed position of the cursor.
+func (t *VirtualTerminal) Cursor() (row, col int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.row, t.col
+}
+
+func (t *VirtualTerminal) CursorVisible() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.cursorVisible
+}
+
+// Returns the cell at the given 0-indexed position.
+func (t *VirtualTerminal) Cell(row, col int) Cell {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if row < 0 || row >= t.height || col < 0 || col >= t.width {
+		return blankCell
+	}
+	return t.cells[row*t.width+col]
+}
+
+// Returns the text content of a row.
+func (t *VirtualTerminal) Line(row int) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.line(row)
+}
+
+func (t *VirtualTerminal) line(row int) string {
+	if row < 0 || row >= t.height {
+		return ""
+	}
+	var builder strings.Builder
+	for _, cell := range t.cells[row*t.width : (row+1)*t.width] {
+		builder.WriteString(cell.Content)
+	}
+	return builder.String()
+}
+
+// Returns the text content of the whole terminal, one line per row.
+func (t *VirtualTerminal) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := make([]string, t.height)
+	for row := range lines {
+		lines[row] = t.line(row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns a description of the styles of all cells that don't have the default
+// style. Each line describes a run of equally styled cells on a row, e.g.
+// "2:0-9 fg=red bold".
+func (t *VirtualTerminal) StyleString() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var builder strings.Builder
+	for row := 0; row < t.height; row++ {
+		start := 0
+		for col := 1; col <= t.width; col++ {
+			style := t.cells[row*t.width+start].Style
+			if col < t.width && t.cells[row*t.width+col].Style == style {
+				continue
+			}
+			if style != (Style{}) {
+				fmt.Fprintf(&builder, "%d:%d-%d %s\n", row, start, col-1, style)
+			}
+			start = col
+		}
+	}
+	return builder.String()
+}
+
+func (t *VirtualTerminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data := append(t.pending, p...)
+	t.pending = nil
+
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b == '\033':
+			n := t.escape(data[i:])
+			if n == 0 {
+				// Wait for the rest of the escape sequence
+				t.pending = append([]byte(nil), data[i:]...)
+				return len(p), nil
+			}
+			i += n
+		case b == '\r':
+			t.moveTo(t.row, 0)
+			i++
+		case b == '\n':
+			t.lineFeed()
+			t.col = 0
+			i++
+		case b == '\b':
+			t.moveTo(t.row, t.col-1)
+			i++
+		case b == '\t':
+			t.moveTo(t.row, (t.col/8+1)*8)
+			i++
+		case b < ' ' || b == 0x7f:
+			i++
+		default:
+			if !utf8.FullRune(data[i:]) {
+				t.pending = append([]byte(nil), data[i:]...)
+				return len(p), nil
+			}
+			r, size := utf8.DecodeRune(data[i:])
+			t.put(r)
+			i += size
+		}
+	}
+	return len(p), nil
+}
+
+func (t *VirtualTerminal) moveTo(row, col int) {
+	t.row = max(0, min(t.height-1, row))
+	t.col = max(0, min(t.width-1, col))
+	t.wrapPending = false
+}
+
+func (t *VirtualTerminal) lineFeed() {
+	t.wrapPending = false
+	if t.row < t.height-1 {
+		t.row++
+		return
+	}
+	// Scroll the whole terminal up by one row
+	copy(t.cells, t.cells[t.width:])
+	t.erase(len(t.cells)-t.width, len(t.cells))
+}
+
+func (t *VirtualTerminal) erase(from, to int) {
+	for i := max(0, from); i < min(len(t.cells), to); i++ {
+		t.cells[i] = Cell{Content: BLANK_CHAR, Width: 1, Style: Style{Bg: t.style.Bg}}
+	}
+}
+
+// Write a character at the cursor position and advance the cursor. Wide
+// characters cover two cells, and characters that continue the grapheme
+// cluster before the cursor are added to its cell.
+func (t *VirtualTerminal) put(r rune) {
+	if t.width == 0 || t.height == 0 {
+		return
+	}
+
+	// Find the cell of the previous character
+	prev := t.row*t.width + t.col
+	if !t.wrapPending {
+		prev--
+	}
+	for prev >= t.row*t.width && t.cells[prev].Width == 0 {
+		prev--
+	}
+	if prev >= t.row*t.width && graphemeExtends(t.cells[prev].Content, r) {
+		t.cells[prev].Content += string(r)
+		return
+	}
+
+	width := runeWidth(r)
+	if width == 0 {
+		return
+	}
+	if t.wrapPending {
+		t.lineFeed()
+		t.col = 0
+	}
+	row := t.cells[t.row*t.width : (t.row+1)*t.width]
+	if t.col+width > t.width && t.col > 0 {
+		// Wide characters that don't fit wrap onto the next line
+		setCell(row, t.col, Cell{Content: BLANK_CHAR, Width: 1, Style: t.style})
+		t.lineFeed()
+		t.col = 0
+		row = t.cells[t.row*t.width : (t.row+1)*t.width]
+	}
+
+	setCell(row, t.col, Cell{Content: string(r), Width: width, Style: t.style})
+	for col := t.col + 1; col < min(t.width, t.col+width); col++ {
+		setCell(row, col, Cell{Style: t.style})
+	}
+	if t.col+width >= t.width {
+		t.col = t.width - 1
+		t.wrapPending = true
+	} else {
+		t.col += width
+	}
+}
+
+// Interpret the escape sequence at the start of data. Returns the number of
+// bytes consumed, or 0 if the sequence is incomplete.
+func (t *VirtualTerminal) escape(data []byte) int {
+	if len(data) < 2 {
+		return 0
+	}
+	switch data[1] {
+	case '[':
+		return t.csi(data)
+	case ']':
+		// Ignore operating system commands, terminated by BEL or ST
+		for i := 2; i < len(data); i++ {
+			if data[i] == '\a' {
+				return i + 1
+			}
+			if data[i] == '\033' && i+1 < len(data) && data[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return 0
+	}
+
+	// Other escape sequences are any number of bytes in the range 0x20-0x2f
+	// followed by a byte in the range 0x30-0x7e
+	for i := 1; i < len(data); i++ {
+		if data[i] >= 0x30 && data[i] <= 0x7e {
+			return i + 1
+		}
+		if data[i] < 0x20 || data[i] > 0x2f {
+			return i
+		}
+	}
+	return 0
+}
+
+// Interpret a control sequence (ESC [ ...). Returns the number of bytes
+// consumed, or 0 if the sequence is incomplete.
+func (t *VirtualTerminal) csi(data []byte) int {
+	end := -1
+	for i := 2; i < len(data); i++ {
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return 0
+	}
+
+	paramString := string(data[2:end])
+	private := strings.HasPrefix(paramString, "?")
+	paramString = strings.TrimPrefix(paramString, "?")
+
+	params := parseParams(paramString)
+	param := func(i, fallback int) int {
+		if i < len(params) && params[i] != 0 {
+			return params[i]
+		}
+		return fallback
+	}
+
+	switch data[end] {
+	case 'H', 'f':
+		t.moveTo(param(0, 1)-1, param(1, 1)-1)
+	case 'A':
+		t.moveTo(t.row-param(0, 1), t.col)
+	case 'B':
+		t.moveTo(t.row+param(0, 1), t.col)
+	case 'C':
+		t.moveTo(t.row, t.col+param(0, 1))
+	case 'D':
+		t.moveTo(t.row, t.col-param(0, 1))
+	case 'G':
+		t.moveTo(t.row, param(0, 1)-1)
+	case 'J':
+		cursor := t.row*t.width + t.col
+		switch param(0, 0) {
+		case 0:
+			t.erase(cursor, len(t.cells))
+		case 1:
+			t.erase(0, cursor+1)
+		case 2, 3:
+			t.erase(0, len(t.cells))
+		}
+	case 'K':
+		start := t.row * t.width
+		cursor := start + t.col
+		switch param(0, 0) {
+		case 0:
+			t.erase(cursor, start+t.width)
+		case 1:
+			t.erase(start, cursor+1)
+		case 2:
+			t.erase(start, start+t.width)
+		}
+	case 'm':
+		t.style.applySGR(params)
+	case 'h', 'l':
+		if private && len(params) > 0 && params[0] == 25 {
+			t.cursorVisible = data[end] == 'h'
+		}
+	}
+	return end + 1
+}
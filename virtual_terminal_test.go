@@ -0,0 +1,47 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+// Sets the foreground to red, selects the US ASCII character set with
+// "ESC ( B", and resets the style.
+const charsetText = "\x1b[31mred\x1b(B\x1b[m ok"
+
+func TestVirtualTerminalEscapes(t *testing.T) {
+	vt := flextui.NewVirtualTerminal(10, 2)
+	vt.Write([]byte(charsetText))
+	// Sequences split across writes are completed by the next write
+	vt.Write([]byte("\r\n\x1b("))
+	vt.Write([]byte("Bnext"))
+
+	if got, want := vt.Line(0), "red ok    "; got != want {
+		t.Errorf("Line 0 is %q, want %q", got, want)
+	}
+	if got, want := vt.Line(1), "next      "; got != want {
+		t.Errorf("Line 1 is %q, want %q", got, want)
+	}
+	if got := vt.Cell(0, 0).Style.Fg; got != flextui.Color_Red {
+		t.Errorf("Cell 0, 0 has foreground %v, want red", got)
+	}
+}
+
+func TestContentEscapes(t *testing.T) {
+	root, vt := flextuitest.NewScreen(10, 1)
+	root.SetContent(charsetText)
+	root.UpdateLayout()
+	root.Render()
+
+	if got, want := vt.Line(0), "red ok    "; got != want {
+		t.Errorf("Line 0 is %q, want %q", got, want)
+	}
+	if got := vt.Cell(0, 0).Style.Fg; got != flextui.Color_Red {
+		t.Errorf("Cell 0, 0 has foreground %v, want red", got)
+	}
+	if got := vt.Cell(0, 4).Style.Fg; got != flextui.Color_Default {
+		t.Errorf("Cell 0, 4 has foreground %v, want the default", got)
+	}
+}
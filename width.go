@@ -0,0 +1,233 @@
+package flextui
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// A range of runes, inclusive.
+type runeRange struct {
+	first, last rune
+}
+
+// Runes with an East Asian Width of Wide or Fullwidth, including emoji that
+// are displayed as wide by default. Ambiguous runes are treated as narrow.
+var wideRanges = []runeRange{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4},
+	{0x17000, 0x18AFF}, {0x1B000, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F1E6, 0x1F1FF}, {0x1F200, 0x1F202},
+	{0x1F210, 0x1F23B}, {0x1F240, 0x1F248}, {0x1F250, 0x1F251}, {0x1F260, 0x1F265},
+	{0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393},
+	{0x1F3A0, 0x1F3CA}, {0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4},
+	{0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440}, {0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D},
+	{0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A}, {0x1F595, 0x1F596},
+	{0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6DC, 0x1F6DF}, {0x1F6EB, 0x1F6EC},
+	{0x1F6F4, 0x1F6FC}, {0x1F7E0, 0x1F7EB}, {0x1F7F0, 0x1F7F0}, {0x1F90C, 0x1F93A},
+	{0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF}, {0x1FA70, 0x1FA7C}, {0x1FA80, 0x1FA89},
+	{0x1FA8F, 0x1FAC6}, {0x1FACE, 0x1FADC}, {0x1FADF, 0x1FAE9}, {0x1FAF0, 0x1FAF8},
+	{0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// Runes with the Extended_Pictographic property, which can be joined into
+// emoji sequences with a zero width joiner.
+var pictographicRanges = []runeRange{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x2199}, {0x21A9, 0x21AA},
+	{0x231A, 0x231B}, {0x2328, 0x2328}, {0x23CF, 0x23CF}, {0x23E9, 0x23F3},
+	{0x23F8, 0x23FA}, {0x24C2, 0x24C2}, {0x25AA, 0x25AB}, {0x25B6, 0x25B6},
+	{0x25C0, 0x25C0}, {0x25FB, 0x25FE}, {0x2600, 0x27BF}, {0x2934, 0x2935},
+	{0x2B05, 0x2B07}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x3030, 0x3030}, {0x303D, 0x303D}, {0x3297, 0x3297}, {0x3299, 0x3299},
+	{0x1F000, 0x1F0FF}, {0x1F10D, 0x1F10F}, {0x1F12F, 0x1F12F}, {0x1F16C, 0x1F171},
+	{0x1F17E, 0x1F17F}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F1AD, 0x1F1E5},
+	{0x1F201, 0x1F20F}, {0x1F21A, 0x1F21A}, {0x1F22F, 0x1F22F}, {0x1F232, 0x1F23A},
+	{0x1F23C, 0x1F23F}, {0x1F249, 0x1F3FA}, {0x1F400, 0x1F53D}, {0x1F546, 0x1F64F},
+	{0x1F680, 0x1F6FF}, {0x1F774, 0x1F77F}, {0x1F7D5, 0x1F7FF}, {0x1F80C, 0x1F80F},
+	{0x1F848, 0x1F84F}, {0x1F85A, 0x1F85F}, {0x1F888, 0x1F88F}, {0x1F8AE, 0x1F8FF},
+	{0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1FAFF}, {0x1FC00, 0x1FFFD},
+}
+
+func inRanges(r rune, ranges []runeRange) bool {
+	// Binary search for the last range that starts at or before r
+	lo, hi := 0, len(ranges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if ranges[mid].first <= r {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo > 0 && r <= ranges[lo-1].last
+}
+
+// Returns the number of terminal columns a rune occupies when it starts a
+// grapheme cluster: 0 for control characters and characters that combine
+// with the previous one, 2 for wide characters, and 1 otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case isControl(r):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || (r >= 0x1160 && r <= 0x11FF):
+		return 0
+	case inRanges(r, wideRanges):
+		return 2
+	}
+	return 1
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// Returns whether a rune extends the grapheme cluster before it regardless of
+// what the cluster is: combining marks, variation selectors, emoji modifiers
+// and joiners.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == 0x200C || r == 0x200D || // Zero width non-joiner and joiner
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // Emoji modifiers
+		(r >= 0xE0020 && r <= 0xE007F) // Tags
+}
+
+// The Hangul syllable types used to keep syllables made of jamo together
+const (
+	hangulNone = iota
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+func hangulType(r rune) int {
+	switch {
+	case (r >= 0x1100 && r <= 0x115F) || (r >= 0xA960 && r <= 0xA97C):
+		return hangulL
+	case (r >= 0x1160 && r <= 0x11A7) || (r >= 0xD7B0 && r <= 0xD7C6):
+		return hangulV
+	case (r >= 0x11A8 && r <= 0x11FF) || (r >= 0xD7CB && r <= 0xD7FB):
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7F && r < 0xA0)
+}
+
+// Returns whether the rune r continues the grapheme cluster, rather than
+// starting a new one, following a simplified version of the rules in Unicode
+// Standard Annex #29.
+func graphemeExtends(cluster string, r rune) bool {
+	if cluster == "" {
+		return false
+	}
+	first, firstSize := utf8.DecodeRuneInString(cluster)
+	last, _ := utf8.DecodeLastRuneInString(cluster)
+
+	// Carriage returns and line feeds stay together, but control characters
+	// are otherwise separate from everything
+	if last == '\r' {
+		return r == '\n'
+	}
+	if isControl(last) || isControl(r) {
+		return false
+	}
+
+	if isGraphemeExtend(r) || (r >= 0xFE00 && r <= 0xFE0F) {
+		return true
+	}
+
+	// Emoji joined by a zero width joiner
+	if last == 0x200D && inRanges(r, pictographicRanges) {
+		return inRanges(first, pictographicRanges)
+	}
+
+	// Flags are made of pairs of regional indicators
+	if isRegionalIndicator(r) {
+		return isRegionalIndicator(first) && firstSize == len(cluster)
+	}
+
+	// Hangul syllables made of jamo
+	switch hangulType(last) {
+	case hangulL:
+		t := hangulType(r)
+		return t == hangulL || t == hangulV || t == hangulLV || t == hangulLVT
+	case hangulV, hangulLV:
+		t := hangulType(r)
+		return t == hangulV || t == hangulT
+	case hangulT, hangulLVT:
+		return hangulType(r) == hangulT
+	}
+	return false
+}
+
+// A grapheme cluster in a string: a sequence of runes that is displayed as a
+// single character.
+type grapheme struct {
+	start, end int // Byte offsets in the string
+	width      int // The number of terminal columns it occupies
+}
+
+// The number of columns between tab stops.
+const tabStop = 8
+
+// Split a string into grapheme clusters. Tabs are as wide as the space up to
+// the next tab stop, counting from the start of their line.
+func graphemes(s string) []grapheme {
+	var clusters []grapheme
+	col := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if n := len(clusters); n > 0 && graphemeExtends(s[clusters[n-1].start:i], r) {
+			clusters[n-1].end = i + size
+		} else {
+			width := runeWidth(r)
+			if r == '\t' {
+				width = tabStop - col%tabStop
+			}
+			clusters = append(clusters, grapheme{start: i, end: i + size, width: width})
+			col += width
+		}
+		if r == '\n' {
+			col = 0
+		}
+		i += size
+	}
+	return clusters
+}
+
+// Returns whether a grapheme cluster of s is a tab.
+func isTab(s string, g grapheme) bool {
+	return s[g.start] == '\t'
+}
+
+// Returns the number of terminal columns that a string occupies, taking wide
+// characters, combining characters, emoji and tabs into account.
+func StringWidth(s string) int {
+	width := 0
+	for _, g := range graphemes(s) {
+		width += g.width
+	}
+	return width
+}
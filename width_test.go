@@ -0,0 +1,77 @@
+package flextui_test
+
+import (
+	"testing"
+
+	"github.com/computerdane/flextui"
+	"github.com/computerdane/flextui/flextuitest"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{"日本語", 6},                  // Wide characters
+		{"e\u0301", 1},              // Combining accent
+		{"a\u200bb", 2},             // Zero width space
+		{"\U0001F44D\U0001F3FD", 2}, // Emoji with a skin tone modifier
+		{"\U0001F1FA\U0001F1F8", 2}, // Regional indicators
+		{"\t", 8},                   // Tabs go to the next tab stop
+		{"+\tfoo()", 13},            // 1 + 7 + 5
+		{"12345678\tx", 17},         // 8 + 8 + 1
+		{"ab\n\tx", 11},             // Tab stops restart on each line
+		// Emoji joined with zero width joiners
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467", 2},
+	}
+	for _, test := range tests {
+		if got := flextui.StringWidth(test.s); got != test.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", test.s, got, test.want)
+		}
+	}
+}
+
+func TestDrawTabs(t *testing.T) {
+	root, vt := flextuitest.NewScreen(16, 2)
+	root.SetContent("+\tfoo()\n\tbar")
+	assertLines(t, renderLines(root, vt),
+		"+       foo()   ",
+		"        bar     ",
+	)
+}
+
+func TestDrawWideCharacters(t *testing.T) {
+	root, vt := flextuitest.NewScreen(6, 3)
+	root.SetIsVertical(true)
+	for _, content := range []string{"日本", "éx", "日本語"} {
+		child := flextui.NewComponent()
+		child.SetContent(content)
+		child.SetWrapMode(flextui.WrapMode_None)
+		root.AddChild(child)
+	}
+	// The last row is narrower than its content, so the wide character that
+	// would be split at the right edge is left blank
+	root.Children()[2].SetMaxCrossLength(5)
+	renderLines(root, vt)
+
+	for _, test := range []struct {
+		row, col int
+		content  string
+		width    int
+	}{
+		{0, 0, "日", 2},
+		{0, 2, "本", 2},
+		{0, 4, " ", 1},
+		{1, 0, "é", 1},
+		{1, 1, "x", 1},
+		{2, 2, "本", 2},
+		{2, 4, " ", 1},
+		{2, 5, " ", 1},
+	} {
+		cell := vt.Cell(test.row, test.col)
+		if cell.Content != test.content || cell.Width != test.width {
+			t.Errorf("Cell %d, %d is %q with width %d, want %q with width %d", test.row, test.col, cell.Content, cell.Width, test.content, test.width)
+		}
+	}
+}